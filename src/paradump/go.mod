@@ -2,24 +2,22 @@ module paradump
 
 go 1.20
 
-require github.com/go-sql-driver/mysql v1.7.1
+require github.com/ErwanMAS/paradump/src/paralib v0.0.0
 
-require github.com/klauspost/compress v1.17.4
+replace github.com/ErwanMAS/paradump/src/paralib => ../paralib
+
+require github.com/go-sql-driver/mysql v1.7.1 // indirect
 
 require (
-	9fans.net/go v0.0.0-20181112161441-237454027057 // indirect
 	github.com/golang-sql/civil v0.0.0-20220223132316-b832511892a9 // indirect
 	github.com/golang-sql/sqlexp v0.1.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
 	github.com/jackc/pgx/v5 v5.5.1 // indirect
 	github.com/jackc/puddle/v2 v2.2.1 // indirect
+	github.com/klauspost/compress v1.17.4 // indirect
 	github.com/microsoft/go-mssqldb v1.6.0 // indirect
-	github.com/rogpeppe/godef v1.1.2 // indirect
 	golang.org/x/crypto v0.12.0 // indirect
-	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/sync v0.1.0 // indirect
-	golang.org/x/sys v0.11.0 // indirect
 	golang.org/x/text v0.12.0 // indirect
-	golang.org/x/tools v0.6.0 // indirect
 )
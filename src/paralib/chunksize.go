@@ -0,0 +1,99 @@
+package paralib
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+//
+// the size of the chunks of a table can follow what the readers observe , the time to read a
+// row and the bytes of a row are averaged over the last chunks of the table . the browser cuts
+// the next chunk to take target_time or to hold target_bytes ( the smallest when both are
+// set ) , within min_size and max_size
+//
+// the size is rounded to 2 significant digits , the browser prepares its query again only
+// when the size changes
+type chunkSizer struct {
+	mutex         sync.Mutex
+	table         string
+	debug         bool
+	target_time   time.Duration
+	target_bytes  int64
+	min_size      int64
+	max_size      int64
+	observed      int
+	nanos_per_row float64
+	bytes_per_row float64
+	last_size     int64
+}
+
+// weight of the last chunk in the averages
+const chunk_sizer_weight = 0.2
+
+func newChunkSizer(table string, debug bool, target_time time.Duration, target_bytes int64, min_size int64, max_size int64) *chunkSizer {
+	return &chunkSizer{table: table, debug: debug, target_time: target_time, target_bytes: target_bytes, min_size: min_size, max_size: max_size}
+}
+
+func (c *chunkSizer) record(rows int, bytes int64, elapsed time.Duration) {
+	if c == nil || rows == 0 {
+		return
+	}
+	nanos_per_row := float64(elapsed.Nanoseconds()) / float64(rows)
+	bytes_per_row := float64(bytes) / float64(rows)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.observed == 0 {
+		c.nanos_per_row = nanos_per_row
+		c.bytes_per_row = bytes_per_row
+	} else {
+		c.nanos_per_row = c.nanos_per_row*(1-chunk_sizer_weight) + nanos_per_row*chunk_sizer_weight
+		c.bytes_per_row = c.bytes_per_row*(1-chunk_sizer_weight) + bytes_per_row*chunk_sizer_weight
+	}
+	c.observed++
+}
+
+// the size of the next chunk , sizeofchunk_init until a chunk of the table is read
+func (c *chunkSizer) size(sizeofchunk_init int64) int64 {
+	if c == nil {
+		return sizeofchunk_init
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.observed == 0 {
+		return sizeofchunk_init
+	}
+	target := math.Inf(1)
+	if c.target_time > 0 && c.nanos_per_row > 0 {
+		target = math.Min(target, float64(c.target_time.Nanoseconds())/c.nanos_per_row)
+	}
+	if c.target_bytes > 0 && c.bytes_per_row > 0 {
+		target = math.Min(target, float64(c.target_bytes)/c.bytes_per_row)
+	}
+	if math.IsInf(target, 1) {
+		return sizeofchunk_init
+	}
+	// rounded before min_size is applied , so rounding down can not go under min_size
+	ret_val := roundChunkSize(int64(math.Min(target, float64(c.max_size))))
+	if ret_val < c.min_size {
+		ret_val = c.min_size
+	}
+	if ret_val != c.last_size {
+		if c.debug {
+			log.Printf("table %s chunk size is %d ( %.0f ns and %.0f bytes per row )", c.table, ret_val, c.nanos_per_row, c.bytes_per_row)
+		}
+		c.last_size = ret_val
+	}
+	return ret_val
+}
+
+// round down to 2 significant digits
+func roundChunkSize(size int64) int64 {
+	unit := int64(1)
+	for size/unit >= 100 {
+		unit = unit * 10
+	}
+	return size / unit * unit
+}
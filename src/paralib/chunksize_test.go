@@ -0,0 +1,59 @@
+package paralib
+
+import (
+	"testing"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+func TestRoundChunkSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want int64
+	}{
+		{0, 0},
+		{7, 7},
+		{99, 99},
+		{100, 100},
+		{155, 150},
+		{1999, 1900},
+		{123456, 120000},
+	}
+	for _, tt := range tests {
+		if got := roundChunkSize(tt.size); got != tt.want {
+			t.Errorf("roundChunkSize(%d) = %d , want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestChunkSizerBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		min_size int64
+		max_size int64
+		rows     int
+		elapsed  time.Duration
+		want     int64
+	}{
+		// 1 s per row gives 1 row per chunk , raised to min_size and not rounded under it
+		{"min not rounded down", 155, 10000, 1, time.Second, 155},
+		// 1 ns per row gives 1e9 rows per chunk , lowered to max_size
+		{"max", 155, 8765, 1000, time.Microsecond, 8700},
+		{"rounded", 10, 100000, 1000, 80 * time.Millisecond, 12000},
+	}
+	for _, tt := range tests {
+		c := newChunkSizer("app.t", false, time.Second, 0, tt.min_size, tt.max_size)
+		if got := c.size(1000); got != 1000 {
+			t.Errorf("%s : size before any chunk = %d , want 1000", tt.name, got)
+		}
+		c.record(tt.rows, 0, tt.elapsed)
+		got := c.size(1000)
+		if got != tt.want {
+			t.Errorf("%s : size = %d , want %d", tt.name, got, tt.want)
+		}
+		if got < tt.min_size || got > tt.max_size {
+			t.Errorf("%s : size %d is out of [%d,%d]", tt.name, got, tt.min_size, tt.max_size)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package paralib
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// passwords as they come from files , environment variables or secret managers
+var test_passwords = []string{
+	"simple",
+	"p@ss/w:rd?",
+	"a@b@c",
+	"ends/with/",
+	"q?x=1&y=2#frag",
+	"%2F%40 percent",
+	"sp ace and 'quote\"",
+	"ünïcødé",
+	"tail@",
+}
+
+// ------------------------------------------------------------------------------------------
+func TestBuildDsnPassword(t *testing.T) {
+	for _, pwd := range test_passwords {
+		for _, user := range []string{"app", "app@prod"} {
+			dsn, err := buildDsn("mysql", "db.local", 3306, user, pwd, "shop", DbConnectOptions{Params: "charset=utf8mb4"}, "maxAllowedPacket=0")
+			if err != nil {
+				t.Fatalf("mysql dsn for %q : %s", pwd, err)
+			}
+			cfg, err := mysql.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("mysql dsn for %q can not be parsed : %s", pwd, err)
+			}
+			if cfg.User != user || cfg.Passwd != pwd || cfg.Addr != "db.local:3306" || cfg.DBName != "shop" || cfg.Params["charset"] != "utf8mb4" {
+				t.Errorf("mysql dsn for %q / %q gives %q / %q on %s/%s", user, pwd, cfg.User, cfg.Passwd, cfg.Addr, cfg.DBName)
+			}
+		}
+		for _, driver := range []string{"postgres", "mssql"} {
+			dsn, err := buildDsn(driver, "db.local", 5432, "app@prod", pwd, "shop", DbConnectOptions{}, "")
+			if err != nil {
+				t.Fatalf("%s dsn for %q : %s", driver, pwd, err)
+			}
+			dsn_url, err := url.Parse(dsn)
+			if err != nil {
+				t.Fatalf("%s dsn for %q can not be parsed : %s", driver, pwd, err)
+			}
+			got, _ := dsn_url.User.Password()
+			if dsn_url.User.Username() != "app@prod" || got != pwd || dsn_url.Host != "db.local:5432" {
+				t.Errorf("%s dsn for %q gives %q / %q on %s", driver, pwd, dsn_url.User.Username(), got, dsn_url.Host)
+			}
+		}
+	}
+	if _, err := buildDsn("mysql", "db.local", 3306, "app:x", "pwd", "shop", DbConnectOptions{}, ""); err == nil {
+		t.Errorf("a mysql user with a colon must be refused")
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestGetPasswordKeepsCharacters(t *testing.T) {
+	for _, pwd := range append(test_passwords, "trailing space ", "two newlines\n") {
+		pwd_file := filepath.Join(t.TempDir(), "pwd")
+		if err := os.WriteFile(pwd_file, []byte(pwd+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := GetPassword("mysql", "db.local", 3306, "app", "shop", "", pwd_file, "", "")
+		if err != nil || got != pwd {
+			t.Errorf("password file with %q gives %q , %v", pwd, got, err)
+		}
+		got, err = GetPassword("mysql", "db.local", 3306, "app", "shop", "", "", "", "cat "+pwd_file)
+		if err != nil || got != pwd {
+			t.Errorf("password command with %q gives %q , %v", pwd, got, err)
+		}
+		// the password goes into a dsn as it was read
+		dsn, _ := buildDsn("mysql", "db.local", 3306, "app", got, "shop", DbConnectOptions{}, "")
+		if cfg, err := mysql.ParseDSN(dsn); err != nil || cfg.Passwd != pwd {
+			t.Errorf("password %q read from a file is not kept in the dsn", pwd)
+		}
+	}
+	got, _ := GetPassword("mysql", "db.local", 3306, "app", "shop", "", "", "", "printf 'crlf\\r\\n'")
+	if got != "crlf" {
+		t.Errorf("password command ending with crlf gives %q", got)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestMysqlOldestPosition(t *testing.T) {
+	ses := func(status bool, name string, pos int) infoMysqlSession {
+		return infoMysqlSession{Status: status, Position: infoMysqlPosition{Name: name, Pos: pos}}
+	}
+	tests := []struct {
+		name     string
+		sessions []infoMysqlSession
+		want     int
+		file     string
+		pos      int
+	}{
+		{"same file", []infoMysqlSession{ses(true, "bin.000002", 900), ses(true, "bin.000002", 400), ses(true, "bin.000002", 100)}, 0, "bin.000002", 400},
+		{"older file", []infoMysqlSession{ses(true, "bin.000003", 4), ses(true, "bin.000002", 9000), ses(true, "bin.000003", 1)}, 0, "bin.000002", 9000},
+		{"failed session skipped", []infoMysqlSession{ses(false, "", 0), ses(true, "bin.000002", 700), ses(true, "bin.000002", 800)}, 0, "bin.000002", 700},
+		{"not enough sessions", []infoMysqlSession{ses(true, "bin.000002", 700), ses(false, "", 0), ses(false, "", 0)}, -1, "", 0},
+	}
+	for _, tt := range tests {
+		var stats_ses []statMysqlSession
+		got := mysqlOldestPosition(tt.sessions, 2, &stats_ses)
+		if got != tt.want {
+			t.Fatalf("%s : index %d , want %d", tt.name, got, tt.want)
+		}
+		if got >= 0 && (stats_ses[got].FileName != tt.file || stats_ses[got].FilePos != tt.pos) {
+			t.Errorf("%s : position %s@%d , want %s@%d", tt.name, stats_ses[got].FileName, stats_ses[got].FilePos, tt.file, tt.pos)
+		}
+	}
+}
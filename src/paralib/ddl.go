@@ -0,0 +1,419 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+//
+// the ddl of a mysql schema is read on a session of the snapshot and written in 2 files
+//
+//	%d-schema.sql   tables , routines and views , loaded before the data
+//	%d-triggers.sql triggers and events , loaded after the data so nothing fires on the rows
+//	                of the dump
+//
+// only the triggers of the dumped tables are written , the routines , the views and the
+// events are the ones of the schema . tables and routines are not qualified by the schema , like
+// in the sql files of the data , views are written as mysql gives them , with the schema
+func ddlFileNames(dumpdir string, dumpfiletemplate string, dbName string) (string, string) {
+	dname := filepath.Dir(dumpfiletemplate)
+	if dname == "." {
+		dname = ""
+	} else {
+		dname = dname + string(os.PathSeparator)
+	}
+	dname = dumpdir + strings.ReplaceAll(dname, "%d", dbName)
+	return dname + dbName + "-schema.sql", dname + dbName + "-triggers.sql"
+}
+
+// ------------------------------------------------------------------------------------------
+// one row of a SHOW CREATE statement , by column name
+func mysqlShowCreate(run_ctx context.Context, adbConn *sql.Conn, query string, objName string) (map[string]string, error) {
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 5*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, query)
+	if q_err != nil {
+		return nil, fmt.Errorf("can not get ddl of %s\n%w", objName, phaseError(ctx, q_err, objName))
+	}
+	defer q_rows.Close()
+	col_names, c_err := q_rows.Columns()
+	if c_err != nil {
+		return nil, fmt.Errorf("can not get ddl of %s\n%w", objName, c_err)
+	}
+	ret_val := make(map[string]string)
+	for q_rows.Next() {
+		values := make([]sql.NullString, len(col_names))
+		scan_args := make([]any, len(col_names))
+		for i := range values {
+			scan_args[i] = &values[i]
+		}
+		err := q_rows.Scan(scan_args...)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan ddl of %s\n%w", objName, err)
+		}
+		for i, col := range col_names {
+			ret_val[col] = values[i].String
+		}
+	}
+	if r_err := q_rows.Err(); r_err != nil {
+		return nil, fmt.Errorf("can not get ddl of %s\n%w", objName, phaseError(ctx, r_err, objName))
+	}
+	return ret_val, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// name and kind of the objects of a schema , from a catalog query with the schema as parameter
+func mysqlListNames(run_ctx context.Context, adbConn *sql.Conn, query string, dbName string) ([][]string, error) {
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, query, dbName)
+	if q_err != nil {
+		return nil, fmt.Errorf("can not list objects of %s\n%w", dbName, phaseError(ctx, q_err, ""))
+	}
+	defer q_rows.Close()
+	var result [][]string
+	for q_rows.Next() {
+		var a_name, a_kind string
+		err := q_rows.Scan(&a_name, &a_kind)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan objects of %s\n%w", dbName, err)
+		}
+		result = append(result, []string{a_name, a_kind})
+	}
+	if r_err := q_rows.Err(); r_err != nil {
+		return nil, fmt.Errorf("can not list objects of %s\n%w", dbName, phaseError(ctx, r_err, ""))
+	}
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// a statement with a body ( routine , trigger , event ) is written between DELIMITER ;;
+func writeDdlStatement(w *strings.Builder, kind string, name string, ddl string, sqlMode string, charSet string, withBody bool) error {
+	if len(ddl) == 0 {
+		return fmt.Errorf("can not get ddl of %s %s , the user may miss some privileges", kind, name)
+	}
+	fmt.Fprintf(w, "\n-- %s %s\n", kind, name)
+	if len(charSet) > 0 {
+		fmt.Fprintf(w, "SET NAMES %s ;\n", charSet)
+	}
+	if withBody {
+		fmt.Fprintf(w, "SET sql_mode = '%s' ;\n", sqlMode)
+		fmt.Fprintf(w, "DELIMITER ;;\n%s ;;\nDELIMITER ;\n", ddl)
+	} else {
+		fmt.Fprintf(w, "%s ;\n", ddl)
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+func writeMysqlSchemaDdl(run_ctx context.Context, adbConn *sql.Conn, dbName string, tables []string, fname_schema string, fname_triggers string) error {
+	var w_schema strings.Builder
+	var w_triggers strings.Builder
+	for _, w := range []*strings.Builder{&w_schema, &w_triggers} {
+		fmt.Fprintf(w, "-- paradump ddl of schema %s\n", dbName)
+		w.WriteString("SET @saved_sql_mode = @@sql_mode ;\nSET NAMES utf8mb4 ;\n")
+	}
+	w_schema.WriteString("SET FOREIGN_KEY_CHECKS = 0 ;\n")
+	w_triggers.WriteString("SET @saved_time_zone = @@time_zone ;\n")
+	// ----------------------------------------------------------------------------------
+	sort.Strings(tables)
+	for _, tb := range tables {
+		full_name := quoteIdentifier(dbName, "``") + "." + quoteIdentifier(tb, "``")
+		res, s_err := mysqlShowCreate(run_ctx, adbConn, "SHOW CREATE TABLE "+full_name, full_name)
+		if s_err != nil {
+			return s_err
+		}
+		if w_err := writeDdlStatement(&w_schema, "table", tb, res["Create Table"], "", "", false); w_err != nil {
+			return w_err
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	routines, l_err := mysqlListNames(run_ctx, adbConn, "select ROUTINE_NAME , ROUTINE_TYPE from INFORMATION_SCHEMA.ROUTINES where ROUTINE_SCHEMA = ? order by ROUTINE_TYPE , ROUTINE_NAME", dbName)
+	if l_err != nil {
+		return l_err
+	}
+	for _, v := range routines {
+		full_name := quoteIdentifier(dbName, "``") + "." + quoteIdentifier(v[0], "``")
+		res, s_err := mysqlShowCreate(run_ctx, adbConn, "SHOW CREATE "+v[1]+" "+full_name, full_name)
+		if s_err != nil {
+			return s_err
+		}
+		if w_err := writeDdlStatement(&w_schema, strings.ToLower(v[1]), v[0], res["Create "+strings.ToUpper(v[1][:1])+strings.ToLower(v[1][1:])], res["sql_mode"], res["character_set_client"], true); w_err != nil {
+			return w_err
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	// a view using another view must be created after it
+	views := make(map[string]string)
+	views_charset := make(map[string]string)
+	var views_todo []string
+	view_names, l_err := mysqlListNames(run_ctx, adbConn, "select TABLE_NAME , TABLE_TYPE from INFORMATION_SCHEMA.TABLES where TABLE_SCHEMA = ? and TABLE_TYPE = 'VIEW' order by TABLE_NAME", dbName)
+	if l_err != nil {
+		return l_err
+	}
+	for _, v := range view_names {
+		full_name := quoteIdentifier(dbName, "``") + "." + quoteIdentifier(v[0], "``")
+		res, s_err := mysqlShowCreate(run_ctx, adbConn, "SHOW CREATE VIEW "+full_name, full_name)
+		if s_err != nil {
+			return s_err
+		}
+		views[v[0]] = res["Create View"]
+		views_charset[v[0]] = res["character_set_client"]
+		views_todo = append(views_todo, v[0])
+	}
+	for len(views_todo) > 0 {
+		var views_next []string
+		for _, vw := range views_todo {
+			depend := false
+			for _, other := range views_todo {
+				if other != vw && strings.Contains(views[vw], quoteIdentifier(other, "``")) {
+					depend = true
+				}
+			}
+			if depend {
+				views_next = append(views_next, vw)
+			} else if w_err := writeDdlStatement(&w_schema, "view", vw, views[vw], "", views_charset[vw], false); w_err != nil {
+				return w_err
+			}
+		}
+		// a cycle , they are written as they are
+		if len(views_next) == len(views_todo) {
+			for _, vw := range views_next {
+				if w_err := writeDdlStatement(&w_schema, "view", vw, views[vw], "", views_charset[vw], false); w_err != nil {
+					return w_err
+				}
+			}
+			views_next = nil
+		}
+		views_todo = views_next
+	}
+	// ----------------------------------------------------------------------------------
+	dumped := make(map[string]bool)
+	for _, tb := range tables {
+		dumped[tb] = true
+	}
+	triggers, l_err := mysqlListNames(run_ctx, adbConn, "select TRIGGER_NAME , EVENT_OBJECT_TABLE from INFORMATION_SCHEMA.TRIGGERS where TRIGGER_SCHEMA = ? order by EVENT_OBJECT_TABLE , ACTION_TIMING , EVENT_MANIPULATION , ACTION_ORDER", dbName)
+	if l_err != nil {
+		return l_err
+	}
+	for _, v := range triggers {
+		if !dumped[v[1]] {
+			continue
+		}
+		full_name := quoteIdentifier(dbName, "``") + "." + quoteIdentifier(v[0], "``")
+		res, s_err := mysqlShowCreate(run_ctx, adbConn, "SHOW CREATE TRIGGER "+full_name, full_name)
+		if s_err != nil {
+			return s_err
+		}
+		if w_err := writeDdlStatement(&w_triggers, "trigger", v[0], res["SQL Original Statement"], res["sql_mode"], res["character_set_client"], true); w_err != nil {
+			return w_err
+		}
+	}
+	events, l_err := mysqlListNames(run_ctx, adbConn, "select EVENT_NAME , STATUS from INFORMATION_SCHEMA.EVENTS where EVENT_SCHEMA = ? order by EVENT_NAME", dbName)
+	if l_err != nil {
+		return l_err
+	}
+	for _, v := range events {
+		full_name := quoteIdentifier(dbName, "``") + "." + quoteIdentifier(v[0], "``")
+		res, s_err := mysqlShowCreate(run_ctx, adbConn, "SHOW CREATE EVENT "+full_name, full_name)
+		if s_err != nil {
+			return s_err
+		}
+		if len(res["time_zone"]) > 0 {
+			fmt.Fprintf(&w_triggers, "\nSET time_zone = '%s' ;", res["time_zone"])
+		}
+		if w_err := writeDdlStatement(&w_triggers, "event", v[0], res["Create Event"], res["sql_mode"], res["character_set_client"], true); w_err != nil {
+			return w_err
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	w_schema.WriteString("\nSET FOREIGN_KEY_CHECKS = 1 ;\n")
+	w_triggers.WriteString("\nSET time_zone = @saved_time_zone ;\n")
+	for _, f := range []struct {
+		name string
+		w    *strings.Builder
+	}{{fname_schema, &w_schema}, {fname_triggers, &w_triggers}} {
+		f.w.WriteString("SET sql_mode = @saved_sql_mode ;\n")
+		w_err := os.WriteFile(f.name, []byte(f.w.String()), 0o644)
+		if w_err != nil {
+			return fmt.Errorf("can not write ddl file %s\n%w", f.name, w_err)
+		}
+		log.Printf("ddl of schema %s written in %s", dbName, f.name)
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// type of a mysql column on the destination
+//
+//	mysql                     postgres              mssql
+//	tinyint                   smallint              smallint ( tinyint when unsigned )
+//	smallint                  smallint / integer    smallint / int        ( unsigned )
+//	mediumint                 integer               int
+//	int                       integer / bigint      int / bigint          ( unsigned )
+//	bigint                    bigint / numeric(20)  bigint / decimal(20)  ( unsigned )
+//	decimal(p,s)              numeric(p,s)          decimal(p,s)
+//	float / double            real / double prec.   real / float
+//	year                      smallint              smallint
+//	date                      date                  date
+//	datetime(p) timestamp(p)  timestamp(p)          datetime2(p)
+//	time(p)                   time(p)               time(p)
+//	char(n) varchar(n)        char(n) varchar(n)    nchar(n) nvarchar(n) ( max above 4000 )
+//	*text enum set json       text                  nvarchar(max)
+//	binary(n) varbinary(n)    bytea                 binary(n) varbinary(n) ( max above 8000 )
+//	*blob bit                 bytea                 varbinary(max)
+//
+// from mysql to mysql the type is the same . bigint unsigned , enum , set , year , bit and json
+// lose what mysql checks on them , this table is also in README.md
+func mapMysqlColumnType(col columnInfo, dstdriver string) (string, error) {
+	if dstdriver == "mysql" {
+		return col.colSqlType, nil
+	}
+	unsigned := strings.Contains(col.colSqlType, "unsigned")
+	size := ""
+	if m := regexp.MustCompile(`\(([0-9]+(,[0-9]+)?)\)`).FindStringSubmatch(col.colSqlType); m != nil {
+		size = m[1]
+	}
+	fract := ""
+	if col.dtPrec > 0 {
+		fract = fmt.Sprintf("(%d)", col.dtPrec)
+	}
+	pick := func(pg string, ms string) string {
+		if dstdriver == "postgres" {
+			return pg
+		}
+		return ms
+	}
+	switch col.colType {
+	case "tinyint":
+		if unsigned {
+			return pick("smallint", "tinyint"), nil
+		}
+		return "smallint", nil
+	case "smallint":
+		if unsigned {
+			return pick("integer", "int"), nil
+		}
+		return "smallint", nil
+	case "mediumint":
+		return pick("integer", "int"), nil
+	case "int", "integer":
+		if unsigned {
+			return "bigint", nil
+		}
+		return pick("integer", "int"), nil
+	case "bigint":
+		if unsigned {
+			return pick("numeric(20)", "decimal(20)"), nil
+		}
+		return "bigint", nil
+	case "decimal", "numeric":
+		return pick("numeric("+size+")", "decimal("+size+")"), nil
+	case "float":
+		return "real", nil
+	case "double":
+		return pick("double precision", "float"), nil
+	case "year":
+		return "smallint", nil
+	case "date":
+		return "date", nil
+	case "datetime", "timestamp":
+		return pick("timestamp"+fract, "datetime2"+fract), nil
+	case "time":
+		return "time" + fract, nil
+	case "char", "varchar":
+		if dstdriver == "postgres" {
+			return col.colType + "(" + size + ")", nil
+		}
+		if n, _ := strconv.Atoi(size); n > 4000 {
+			return "nvarchar(max)", nil
+		}
+		return "n" + col.colType + "(" + size + ")", nil
+	case "tinytext", "text", "mediumtext", "longtext", "enum", "set", "json":
+		return pick("text", "nvarchar(max)"), nil
+	case "binary", "varbinary":
+		if dstdriver == "postgres" {
+			return "bytea", nil
+		}
+		if n, _ := strconv.Atoi(size); n > 8000 {
+			return "varbinary(max)", nil
+		}
+		return col.colType + "(" + size + ")", nil
+	case "tinyblob", "blob", "mediumblob", "longblob", "bit":
+		return pick("bytea", "varbinary(max)"), nil
+	}
+	return "", fmt.Errorf("no type on %s for the type %s of column %s", dstdriver, col.colSqlType, col.colName)
+}
+
+// ------------------------------------------------------------------------------------------
+// the table is created when it does not exist , with the columns read from the source and the
+// primary key ( an unique key when the primary key is guessed from an index ) . the names are
+// quoted like in the insert queries , only on mysql
+func getCreateTableQuery(a_table metadataTable, dstdriver string) (string, error) {
+	tablequote := ""
+	if dstdriver == "mysql" {
+		tablequote = "``"
+	}
+	var cols []string
+	for _, col := range a_table.columnInfos {
+		col_type, t_err := mapMysqlColumnType(col, dstdriver)
+		if t_err != nil {
+			return "", t_err
+		}
+		a_col := fmt.Sprintf("%s %s", quoteIdentifier(col.colName, tablequote), col_type)
+		if !col.isNullable {
+			a_col = a_col + " NOT NULL"
+		}
+		cols = append(cols, a_col)
+	}
+	key_kind := "PRIMARY KEY"
+	if a_table.fakePrimaryKey {
+		key_kind = "UNIQUE"
+	}
+	if len(a_table.primaryKey) > 0 {
+		cols = append(cols, fmt.Sprintf("%s ( %s )", key_kind, generateListPkCols4Sql(a_table.primaryKey, "", tablequote)))
+	}
+	switch dstdriver {
+	case "mysql":
+		return fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s`.`%s` (\n  %s\n)", a_table.dstDbName, a_table.tbName, strings.Join(cols, " ,\n  ")), nil
+	case "mssql":
+		return fmt.Sprintf("IF OBJECT_ID(N'%s.%s', N'U') IS NULL CREATE TABLE %s.%s (\n  %s\n)", a_table.dstDbName, a_table.tbName, a_table.dstDbName, a_table.tbName, strings.Join(cols, " ,\n  ")), nil
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (\n  %s\n)", a_table.dstDbName, a_table.tbName, strings.Join(cols, " ,\n  ")), nil
+}
+
+// ------------------------------------------------------------------------------------------
+func createTablesOnDestination(run_ctx context.Context, srcdriver string, dstdriver string, adbConn *sql.Conn, infTables []metadataTable) error {
+	mode_debug := isDebug(run_ctx)
+	if srcdriver != "mysql" {
+		return fmt.Errorf("tables can only be created from a mysql source")
+	}
+	for _, a_table := range infTables {
+		query, q_err := getCreateTableQuery(a_table, dstdriver)
+		if q_err != nil {
+			return q_err
+		}
+		if mode_debug {
+			log.Printf("create table %s.%s on destination\n%s", a_table.dstDbName, a_table.tbName, query)
+		}
+		ctx, cancel := phaseContext(run_ctx, timeout_write, 0)
+		_, e_err := adbConn.ExecContext(ctx, query)
+		cancel()
+		if e_err != nil {
+			return fmt.Errorf("can not create table %s.%s on destination\n%w", a_table.dstDbName, a_table.tbName, phaseError(ctx, e_err, a_table.dstDbName+"."+a_table.tbName))
+		}
+	}
+	log.Printf("%d tables are created on destination when they were missing", len(infTables))
+	return nil
+}
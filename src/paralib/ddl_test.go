@@ -0,0 +1,61 @@
+package paralib
+
+import (
+	"testing"
+)
+
+// ------------------------------------------------------------------------------------------
+func TestMapMysqlColumnType(t *testing.T) {
+	tests := []struct {
+		col_type     string
+		col_sql_type string
+		dt_prec      int
+		pg           string
+		ms           string
+	}{
+		{"tinyint", "tinyint(4)", 0, "smallint", "smallint"},
+		{"tinyint", "tinyint(3) unsigned", 0, "smallint", "tinyint"},
+		{"smallint", "smallint(5) unsigned", 0, "integer", "int"},
+		{"mediumint", "mediumint(8) unsigned", 0, "integer", "int"},
+		{"int", "int(11)", 0, "integer", "int"},
+		{"int", "int(10) unsigned", 0, "bigint", "bigint"},
+		{"bigint", "bigint(20)", 0, "bigint", "bigint"},
+		{"bigint", "bigint(20) unsigned", 0, "numeric(20)", "decimal(20)"},
+		{"decimal", "decimal(12,4)", 0, "numeric(12,4)", "decimal(12,4)"},
+		{"float", "float", 0, "real", "real"},
+		{"double", "double", 0, "double precision", "float"},
+		{"year", "year(4)", 0, "smallint", "smallint"},
+		{"date", "date", 0, "date", "date"},
+		{"datetime", "datetime", 0, "timestamp", "datetime2"},
+		{"timestamp", "timestamp(6)", 6, "timestamp(6)", "datetime2(6)"},
+		{"time", "time(3)", 3, "time(3)", "time(3)"},
+		{"varchar", "varchar(255)", 0, "varchar(255)", "nvarchar(255)"},
+		{"varchar", "varchar(5000)", 0, "varchar(5000)", "nvarchar(max)"},
+		{"char", "char(2)", 0, "char(2)", "nchar(2)"},
+		{"longtext", "longtext", 0, "text", "nvarchar(max)"},
+		{"enum", "enum('a','b')", 0, "text", "nvarchar(max)"},
+		{"set", "set('a','b')", 0, "text", "nvarchar(max)"},
+		{"json", "json", 0, "text", "nvarchar(max)"},
+		{"varbinary", "varbinary(16)", 0, "bytea", "varbinary(16)"},
+		{"binary", "binary(9000)", 0, "bytea", "varbinary(max)"},
+		{"blob", "blob", 0, "bytea", "varbinary(max)"},
+		{"bit", "bit(1)", 0, "bytea", "varbinary(max)"},
+	}
+	for _, tt := range tests {
+		col := columnInfo{colName: "c", colType: tt.col_type, colSqlType: tt.col_sql_type, dtPrec: tt.dt_prec}
+		if got, err := mapMysqlColumnType(col, "postgres"); err != nil || got != tt.pg {
+			t.Errorf("%s to postgres = %s %v , want %s", tt.col_sql_type, got, err, tt.pg)
+		}
+		if got, err := mapMysqlColumnType(col, "mssql"); err != nil || got != tt.ms {
+			t.Errorf("%s to mssql = %s %v , want %s", tt.col_sql_type, got, err, tt.ms)
+		}
+		if got, err := mapMysqlColumnType(col, "mysql"); err != nil || got != tt.col_sql_type {
+			t.Errorf("%s to mysql = %s %v , want the same type", tt.col_sql_type, got, err)
+		}
+	}
+	// a type without a mapping is an error , not a panic
+	col := columnInfo{colName: "c", colType: "geometry", colSqlType: "geometry"}
+	if got, err := mapMysqlColumnType(col, "postgres"); err == nil {
+		t.Errorf("geometry to postgres = %s , want an error", got)
+	}
+}
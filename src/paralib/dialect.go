@@ -0,0 +1,269 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ------------------------------------------------------------------------------------------
+//
+// everything that change from one source database to another one when we build queries
+// to browse and read a table . the dml send to the destination is not managed here .
+type sqlDialect interface {
+	// two chars , the opening and the closing quote of an identifier
+	IdentQuotes() string
+	// placeholder format , "?" or a format with the position of the parameter ( "$%d" )
+	PlaceholderFormat() string
+	// select cols from a table with an optional where , sorted and limited
+	SelectWithLimit(cols string, from string, where string, orderby string, limit string) string
+	// query to get the next pk when we use an index as primary key , return also a counter of rows
+	FakePkCounterQuery(outerCols string, innerCols string, from string, where string, orderby string, orderbydesc string, limit string) string
+	// query to collect indexes , return column name , cardinality , index name ( 2 parameters schema and table )
+	IndexInfoQuery(withPrimary bool) string
+	// query to list the tables of a schema ( 1 parameter schema )
+	ListTablesQuery() string
+	// query to check if a schema exists ( 1 parameter schema )
+	SchemaExistsQuery() string
+	// query to list the schemas , the system schemas are not listed
+	ListSchemasQuery() string
+	// query to identify the server , return an unique id and the version
+	ServerInfoQuery() string
+	// query to collect the secondary indexes that are not constraints , return index name ,
+	// create ddl ( null when the index can not be rebuilt ) , drop ddl ( 2 parameters schema and table )
+	SecondaryIndexesQuery() string
+	BasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error)
+}
+
+// ------------------------------------------------------------------------------------------
+func getSqlDialect(driver string) sqlDialect {
+	if driver == "postgres" {
+		return postgresDialect{}
+	}
+	if driver == "mssql" {
+		return mssqlDialect{}
+	}
+	return mysqlDialect{}
+}
+
+// ------------------------------------------------------------------------------------------
+func sqlPlaceholder(d sqlDialect, pos int) string {
+	if len(d.PlaceholderFormat()) > 2 {
+		return fmt.Sprintf(d.PlaceholderFormat(), pos)
+	}
+	return d.PlaceholderFormat()
+}
+
+// ------------------------------------------------------------------------------------------
+func quoteIdentifier(name string, tablequote string) string {
+	if len(tablequote) < 2 {
+		return name
+	}
+	return string(tablequote[0]) + name + string(tablequote[1])
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// counter done with row_number() , for databases without user variables
+func windowFakePkCounterQuery(d sqlDialect, outerCols string, innerCols string, from string, where string, orderby string, orderbydesc string, limit string) string {
+	inner := d.SelectWithLimit(fmt.Sprintf("%s , row_number() over ( order by %s ) as _cnt_pkey", innerCols, orderby), from, where, orderby, limit)
+	return d.SelectWithLimit(outerCols+" , _cnt_pkey", "( "+inner+" ) e", "", orderbydesc, "1")
+}
+
+// ------------------------------------------------------------------------------------------
+type mysqlDialect struct{}
+
+func (mysqlDialect) IdentQuotes() string {
+	return "``"
+}
+
+func (mysqlDialect) PlaceholderFormat() string {
+	return "?"
+}
+
+func (mysqlDialect) SelectWithLimit(cols string, from string, where string, orderby string, limit string) string {
+	if len(where) > 0 {
+		return fmt.Sprintf("select %s from %s where %s order by %s limit %s ", cols, from, where, orderby, limit)
+	}
+	return fmt.Sprintf("select %s from %s order by %s limit %s ", cols, from, orderby, limit)
+}
+
+func (mysqlDialect) FakePkCounterQuery(outerCols string, innerCols string, from string, where string, orderby string, orderbydesc string, limit string) string {
+	return fmt.Sprintf("select %s,cast(@cnt as unsigned integer ) as _cnt_pkey from ( select %s,@cnt:=@cnt+1 from %s , ( select @cnt := 0 ) c where %s order by %s limit %s ) e order by %s limit 1 ",
+		outerCols, innerCols, from, where, orderby, limit, orderbydesc)
+}
+
+func (mysqlDialect) IndexInfoQuery(withPrimary bool) string {
+	filter_collect_index := " and INDEX_NAME != 'PRIMARY' "
+	if withPrimary {
+		filter_collect_index = ""
+	}
+	return "select COLUMN_NAME,coalesce(CARDINALITY,0),INDEX_NAME  from INFORMATION_SCHEMA.STATISTICS WHERE  table_schema = ? and table_name = ? " + filter_collect_index + " order by INDEX_NAME,SEQ_IN_INDEX"
+}
+
+func (mysqlDialect) ListTablesQuery() string {
+	return "select TABLE_name from information_schema.tables WHERE table_schema = ? and TABLE_TYPE='BASE TABLE' order by table_name "
+}
+
+func (mysqlDialect) SchemaExistsQuery() string {
+	return "SELECT count(*) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ? "
+}
+
+// metrics_schema is a system schema of tidb
+func (mysqlDialect) ListSchemasQuery() string {
+	return "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE lower(SCHEMA_NAME) not in ('mysql','sys','performance_schema','information_schema','metrics_schema') order by SCHEMA_NAME "
+}
+
+func (mysqlDialect) ServerInfoQuery() string {
+	return "select @@server_uuid , @@version"
+}
+
+// functional indexes have no column name , they can not be rebuilt
+func (mysqlDialect) SecondaryIndexesQuery() string {
+	return "select INDEX_NAME , case when count(*) = count(COLUMN_NAME) then concat('ALTER TABLE `',TABLE_SCHEMA,'`.`',TABLE_NAME,'` ADD ', " +
+		"            case when INDEX_TYPE in ('FULLTEXT','SPATIAL') then concat(INDEX_TYPE,' ') when NON_UNIQUE = 0 then 'UNIQUE ' else '' end , " +
+		"            'INDEX `',INDEX_NAME,'` (',group_concat(concat('`',COLUMN_NAME,'`',coalesce(concat('(',SUB_PART,')'),''),case when COLLATION = 'D' then ' DESC' else '' end) order by SEQ_IN_INDEX separator ','),')') end , " +
+		"       concat('ALTER TABLE `',TABLE_SCHEMA,'`.`',TABLE_NAME,'` DROP INDEX `',INDEX_NAME,'`') " +
+		"  from INFORMATION_SCHEMA.STATISTICS WHERE table_schema = ? and table_name = ? and INDEX_NAME != 'PRIMARY' " +
+		" group by TABLE_SCHEMA , TABLE_NAME , INDEX_NAME , INDEX_TYPE , NON_UNIQUE order by INDEX_NAME "
+}
+
+func (mysqlDialect) BasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	return getMysqlBasicMetadataInfo(run_ctx, adbConn, dbName, tableName)
+}
+
+// ------------------------------------------------------------------------------------------
+type postgresDialect struct{}
+
+func (postgresDialect) IdentQuotes() string {
+	return `""`
+}
+
+func (postgresDialect) PlaceholderFormat() string {
+	return "$%d"
+}
+
+func (postgresDialect) SelectWithLimit(cols string, from string, where string, orderby string, limit string) string {
+	if len(where) > 0 {
+		return fmt.Sprintf("select %s from %s where %s order by %s limit %s ", cols, from, where, orderby, limit)
+	}
+	return fmt.Sprintf("select %s from %s order by %s limit %s ", cols, from, orderby, limit)
+}
+
+func (d postgresDialect) FakePkCounterQuery(outerCols string, innerCols string, from string, where string, orderby string, orderbydesc string, limit string) string {
+	return windowFakePkCounterQuery(d, outerCols, innerCols, from, where, orderby, orderbydesc, limit)
+}
+
+// only the last column of an unique index get a cardinality , prefixes of an unique index are not unique
+func (postgresDialect) IndexInfoQuery(withPrimary bool) string {
+	filter_collect_index := " and not ix.indisprimary "
+	if withPrimary {
+		filter_collect_index = ""
+	}
+	return "select a.attname , case when ix.indisunique and a.attnum = ix.indkey[ix.indnkeyatts-1] then greatest(t.reltuples,0)::bigint else 0 end , i.relname " +
+		"  from pg_index ix join pg_class t on t.oid = ix.indrelid join pg_class i on i.oid = ix.indexrelid join pg_namespace n on n.oid = t.relnamespace " +
+		"  join pg_attribute a on a.attrelid = t.oid and a.attnum = any(ix.indkey) " +
+		" where n.nspname = $1 and t.relname = $2 and array_position(ix.indkey,a.attnum) < ix.indnkeyatts " + filter_collect_index +
+		" order by i.relname , array_position(ix.indkey,a.attnum) "
+}
+
+func (postgresDialect) ListTablesQuery() string {
+	return "select TABLE_name from information_schema.tables WHERE table_schema = $1 and TABLE_TYPE='BASE TABLE' order by table_name "
+}
+
+func (postgresDialect) SchemaExistsQuery() string {
+	return "SELECT count(*) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = $1 "
+}
+
+func (postgresDialect) ListSchemasQuery() string {
+	return "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME not in ('pg_catalog','information_schema') and SCHEMA_NAME not like 'pg\\_toast%' and SCHEMA_NAME not like 'pg\\_temp\\_%' order by SCHEMA_NAME "
+}
+
+func (postgresDialect) ServerInfoQuery() string {
+	return "select system_identifier::text , version() from pg_control_system()"
+}
+
+func (postgresDialect) SecondaryIndexesQuery() string {
+	return "select i.relname , pg_get_indexdef(ix.indexrelid) , 'DROP INDEX ' || quote_ident(n.nspname) || '.' || quote_ident(i.relname) " +
+		"  from pg_index ix join pg_class t on t.oid = ix.indrelid join pg_class i on i.oid = ix.indexrelid join pg_namespace n on n.oid = t.relnamespace " +
+		" where n.nspname = $1 and t.relname = $2 and not ix.indisprimary and not exists ( select 1 from pg_constraint c where c.conindid = ix.indexrelid ) " +
+		" order by i.relname "
+}
+
+func (postgresDialect) BasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	return getPostgresBasicMetadataInfo(run_ctx, adbConn, dbName, tableName)
+}
+
+// ------------------------------------------------------------------------------------------
+type mssqlDialect struct{}
+
+func (mssqlDialect) IdentQuotes() string {
+	return "[]"
+}
+
+func (mssqlDialect) PlaceholderFormat() string {
+	return "@p%d"
+}
+
+func (mssqlDialect) SelectWithLimit(cols string, from string, where string, orderby string, limit string) string {
+	if len(where) > 0 {
+		return fmt.Sprintf("select top %s %s from %s where %s order by %s ", limit, cols, from, where, orderby)
+	}
+	return fmt.Sprintf("select top %s %s from %s order by %s ", limit, cols, from, orderby)
+}
+
+func (d mssqlDialect) FakePkCounterQuery(outerCols string, innerCols string, from string, where string, orderby string, orderbydesc string, limit string) string {
+	return windowFakePkCounterQuery(d, outerCols, innerCols, from, where, orderby, orderbydesc, limit)
+}
+
+// only the last column of an unique index get a cardinality , prefixes of an unique index are not unique
+func (mssqlDialect) IndexInfoQuery(withPrimary bool) string {
+	filter_collect_index := " and i.is_primary_key = 0 "
+	if withPrimary {
+		filter_collect_index = ""
+	}
+	return "select c.name , case when i.is_unique = 1 and ic.key_ordinal = ( select max(x.key_ordinal) from sys.index_columns x where x.object_id = i.object_id and x.index_id = i.index_id ) " +
+		"            then ( select coalesce(sum(p.rows),0) from sys.partitions p where p.object_id = t.object_id and p.index_id in (0,1) ) else 0 end , i.name " +
+		"  from sys.indexes i join sys.tables t on t.object_id = i.object_id join sys.schemas s on s.schema_id = t.schema_id " +
+		"  join sys.index_columns ic on ic.object_id = i.object_id and ic.index_id = i.index_id and ic.key_ordinal > 0 " +
+		"  join sys.columns c on c.object_id = ic.object_id and c.column_id = ic.column_id " +
+		" where s.name = @p1 and t.name = @p2 and i.type > 0 " + filter_collect_index +
+		" order by i.name , ic.key_ordinal "
+}
+
+func (mssqlDialect) ListTablesQuery() string {
+	return "select TABLE_name from information_schema.tables WHERE table_schema = @p1 and TABLE_TYPE='BASE TABLE' order by table_name "
+}
+
+func (mssqlDialect) SchemaExistsQuery() string {
+	return "SELECT count(*) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = @p1 "
+}
+
+// db_owner , db_datareader ... are the schemas of the fixed database roles
+func (mssqlDialect) ListSchemasQuery() string {
+	return "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME not in ('sys','INFORMATION_SCHEMA','guest') and SCHEMA_NAME not like 'db[_]%' order by SCHEMA_NAME "
+}
+
+func (mssqlDialect) ServerInfoQuery() string {
+	return "select cast(SERVERPROPERTY('ServerName') as nvarchar(256)) , @@VERSION"
+}
+
+// only the nonclustered indexes , a clustered one is the storage of the table
+func (mssqlDialect) SecondaryIndexesQuery() string {
+	return "select i.name , 'CREATE ' + case when i.is_unique = 1 then 'UNIQUE ' else '' end + 'NONCLUSTERED INDEX ' + quotename(i.name) + ' ON ' + quotename(s.name) + '.' + quotename(t.name) + ' (' + " +
+		"       ( select string_agg(quotename(c.name) + case when ic.is_descending_key = 1 then ' DESC' else '' end,',') within group ( order by ic.key_ordinal ) " +
+		"           from sys.index_columns ic join sys.columns c on c.object_id = ic.object_id and c.column_id = ic.column_id " +
+		"          where ic.object_id = i.object_id and ic.index_id = i.index_id and ic.is_included_column = 0 ) + ')' + " +
+		"       coalesce(' INCLUDE (' + ( select string_agg(quotename(c.name),',') " +
+		"           from sys.index_columns ic join sys.columns c on c.object_id = ic.object_id and c.column_id = ic.column_id " +
+		"          where ic.object_id = i.object_id and ic.index_id = i.index_id and ic.is_included_column = 1 ) + ')','') + " +
+		"       coalesce(' WHERE ' + i.filter_definition,'') , " +
+		"       'DROP INDEX ' + quotename(i.name) + ' ON ' + quotename(s.name) + '.' + quotename(t.name) " +
+		"  from sys.indexes i join sys.tables t on t.object_id = i.object_id join sys.schemas s on s.schema_id = t.schema_id " +
+		" where s.name = @p1 and t.name = @p2 and i.type = 2 and i.is_primary_key = 0 and i.is_unique_constraint = 0 " +
+		" order by i.name "
+}
+
+func (mssqlDialect) BasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	return getMsSqlBasicMetadataInfo(run_ctx, adbConn, dbName, tableName)
+}
@@ -0,0 +1,3086 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ------------------------------------------------------------------------------------------
+func getTableMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string, where string, columns columnFilter, guessPk bool, dumpmode string, dumpinsertwithcol string, srcdriver string, dstdriver string) (metadataTable, error) {
+	mode_debug := isDebug(run_ctx)
+	dialect := getSqlDialect(srcdriver)
+	result, m_err := dialect.BasicMetadataInfo(run_ctx, adbConn, dbName, tableName)
+	if m_err != nil {
+		return result, m_err
+	}
+	result.where = where
+	if srcdriver == "mysql" {
+		result.partitions, m_err = getMysqlPartitions(run_ctx, adbConn, dbName, tableName)
+		if m_err != nil {
+			return result, m_err
+		}
+		if len(result.partitions) > 0 {
+			log.Printf("table %s has %d partitions , they are browsed one by one", result.fullName, len(result.partitions))
+		}
+	}
+
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err := adbConn.QueryContext(ctx, dialect.IndexInfoQuery(false), dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query %s catalog to get index infos for %s.%s\n%w", srcdriver, dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	var idx_cur indexInfo
+	for q_rows.Next() {
+		var a_cardina int64
+		var a_col_name string
+		var a_idx_name string
+
+		err := q_rows.Scan(&a_col_name, &a_cardina, &a_idx_name)
+		if err != nil {
+			return result, fmt.Errorf("can not scan indexes informations for %s.%s\n%w", dbName, tableName, err)
+		}
+		if len(idx_cur.idxName) != 0 && idx_cur.idxName != a_idx_name {
+			result.Indexes = append(result.Indexes, idx_cur)
+			idx_cur.columns = nil
+		}
+		if len(idx_cur.columns) > 0 {
+			result.Indexes = append(result.Indexes, idx_cur)
+		}
+		idx_cur.idxName = a_idx_name
+		idx_cur.cardinality = a_cardina
+		for _, v := range result.columnInfos {
+			if v.colName == a_col_name {
+				idx_cur.columns = append(idx_cur.columns, v)
+			}
+		}
+		if mode_debug {
+			log.Printf("idx_cur %v", idx_cur)
+		}
+	}
+	if len(idx_cur.idxName) != 0 {
+		result.Indexes = append(result.Indexes, idx_cur)
+	}
+
+	if len(result.primaryKey) == 0 {
+		if !guessPk {
+			result.onError = result.onError | 1
+		} else {
+			log.Printf("table %s.%s has no primary key\n", dbName, tableName)
+			if mode_debug {
+				log.Printf("table info is :")
+				log.Printf("t.dbName         : %s", result.dbName)
+				log.Printf("t.tbName         : %s", result.tbName)
+				log.Printf("t.cntRows        : %d", result.cntRows)
+				log.Printf("t.sizeBytes      : %d", result.sizeBytes)
+				log.Printf("t.storageEng     : %s", result.storageEng)
+				var a_str string
+				for _, v := range result.columnInfos {
+					if len(a_str) == 0 {
+						a_str = v.colName
+					} else {
+						a_str = a_str + " , " + v.colName
+					}
+				}
+				log.Printf("t.columnInfos    : [ %s ] ", a_str)
+				log.Printf("t.primaryKey     : %s", result.primaryKey)
+				log.Printf("t.Indexes        : ")
+				for _, i := range result.Indexes {
+					var a_str string
+					for _, v := range i.columns {
+						if len(a_str) == 0 {
+							a_str = v.colName
+						} else {
+							a_str = a_str + " , " + v.colName
+						}
+					}
+					log.Printf("         -  %9d [ %s ] %s ", i.cardinality, a_str, i.idxName)
+				}
+				log.Printf("t.fakePrimaryKey : %t", result.fakePrimaryKey)
+				log.Printf("t.onError        : %d", result.onError)
+			}
+			// -----------------------------------------------------------------
+			//
+			// result.Indexes contains a list of all indexes AND in case of multi-columns indexes ALL implicit indexes you can have
+			//
+			var max_cardinality int64
+			max_cardinality = -1
+			ix_pos := -1
+			for i, ix := range result.Indexes {
+				ix_have_null := false
+				for _, colinfo := range ix.columns {
+					ix_have_null = ix_have_null || colinfo.isNullable
+				}
+				if !ix_have_null && ix.cardinality > max_cardinality {
+					ix_pos = i
+					max_cardinality = ix.cardinality
+				}
+			}
+			// -----------------------------------------------------------------
+			if ix_pos == -1 {
+				result.onError = result.onError | 2
+			} else {
+				// ---------------------------------------------------------
+				if mode_debug {
+					log.Printf("we choose index %d", ix_pos)
+				}
+				result.fakePrimaryKey = true
+				for _, colinfo := range result.Indexes[ix_pos].columns {
+					result.primaryKey = append(result.primaryKey, colinfo.colName)
+				}
+				// ---------------------------------------------------------
+			}
+		}
+	}
+	result.cntPkCols = len(result.primaryKey)
+	// ---------------------------------------------------------------------------------
+	enumPkCols := make([]string, 0)
+	for _, v := range result.columnInfos {
+		if v.colType == "enum" {
+			for _, pk := range result.primaryKey {
+				if pk == v.colName {
+					enumPkCols = append(enumPkCols, pk)
+				}
+			}
+		}
+	}
+	if mode_debug {
+		if len(enumPkCols) > 0 {
+			log.Printf(" for table %s we have enum in pk for column %s", result.fullName, enumPkCols)
+		}
+	}
+	// ---------------------------
+	// the pk is known , the columns that are not read can be removed
+	if !columns.isEmpty() {
+		result.columnInfos = columns.project(result.fullName, result.columnInfos, result.primaryKey)
+		result.cntCols = len(result.columnInfos)
+		result.projected = true
+	}
+	// ---------------------------
+	tablequote := dialect.IdentQuotes()
+	sql_cond_lower_pk, qry_indices_lo_bound := generatePredicat(result.primaryKey, true, enumPkCols, tablequote, dialect.PlaceholderFormat(), 1)
+	sql_cond_upper_pk, qry_indices_up_bound := generatePredicat(result.primaryKey, false, enumPkCols, tablequote, dialect.PlaceholderFormat(), 1+len(qry_indices_lo_bound))
+	sql_cond_equal_pk, qry_indices_equality := generateEqualityPredicat(result.primaryKey, enumPkCols, tablequote, dialect.PlaceholderFormat())
+	// ---------------------------
+	result.listColsSQL = generateListCols4Sql(result.columnInfos, tablequote)
+	result.listColsPkSQL = generateListPkCols4Sql(result.primaryKey, "", tablequote)
+	result.listColsPkFetchSQL = generateListPkColsFetch4Sql(result.primaryKey, enumPkCols, tablequote)
+	result.listColsPkOrderSQL = generateListPkCols4Sql(result.primaryKey, "", tablequote)
+	result.listColsPkOrderDescSQL = generateListPkCols4Sql(result.primaryKey, "desc", tablequote)
+	result.listColsCSV = generateListCols4Csv(result.columnInfos)
+	// ---------------------------
+	// the filter of the table is in every query , the chunks are still done on the pk
+	sql_filter := sqlTableWhere(result.where)
+	// the next query is a template for the limit , a % of the filter must stay a %
+	sql_next_filter := strings.ReplaceAll(sql_filter, "%", "%%")
+	sql_first_filter := ""
+	if len(result.where) > 0 {
+		sql_first_filter = "( " + result.where + " )"
+	}
+	// ---------------------------
+	// the queries reading rows are built on from , the table or one of its partitions
+	read_queries := func(from string) readQueries {
+		var q readQueries
+		// the limit of the next query is set by the browser , it stays as %d in the template
+		q.query_for_browser_first = dialect.SelectWithLimit(result.listColsPkFetchSQL, from, sql_first_filter, result.listColsPkOrderSQL, "1")
+		if result.fakePrimaryKey {
+			q.query_for_browser_next = dialect.FakePkCounterQuery(result.listColsPkSQL, result.listColsPkFetchSQL, from, sql_cond_lower_pk+sql_next_filter, result.listColsPkOrderSQL, result.listColsPkOrderDescSQL, "%d")
+		} else {
+			q.query_for_browser_next = dialect.SelectWithLimit(result.listColsPkSQL, "( "+dialect.SelectWithLimit(result.listColsPkFetchSQL, from, sql_cond_lower_pk+sql_next_filter, result.listColsPkOrderSQL, "%d")+" ) e", "", result.listColsPkOrderDescSQL, "1")
+		}
+		// a range of the pk browsed alone , from a lower bound to an upper bound excluded , the
+		// parameters are the ones of the interval query
+		sql_cond_range_pk := sql_cond_lower_pk + " and " + sql_cond_upper_pk
+		q.query_for_browser_first_lower = dialect.SelectWithLimit(result.listColsPkFetchSQL, from, sql_cond_lower_pk+sql_filter, result.listColsPkOrderSQL, "1")
+		q.query_for_browser_first_range = dialect.SelectWithLimit(result.listColsPkFetchSQL, from, sql_cond_range_pk+sql_filter, result.listColsPkOrderSQL, "1")
+		if result.fakePrimaryKey {
+			q.query_for_browser_next_range = dialect.FakePkCounterQuery(result.listColsPkSQL, result.listColsPkFetchSQL, from, sql_cond_range_pk+sql_next_filter, result.listColsPkOrderSQL, result.listColsPkOrderDescSQL, "%d")
+		} else {
+			q.query_for_browser_next_range = dialect.SelectWithLimit(result.listColsPkSQL, "( "+dialect.SelectWithLimit(result.listColsPkFetchSQL, from, sql_cond_range_pk+sql_next_filter, result.listColsPkOrderSQL, "%d")+" ) e", "", result.listColsPkOrderDescSQL, "1")
+		}
+		// ---------------------------
+		q.query_for_reader_equality = fmt.Sprintf("/* paradump */ select %s from %s where ( %s )%s           ", result.listColsSQL, from, sql_cond_equal_pk, sql_filter)
+		q.query_for_reader_interval = fmt.Sprintf("/* paradump */ select %s from %s where ( %s ) and ( %s)%s ", result.listColsSQL, from, sql_cond_lower_pk, sql_cond_upper_pk, sql_filter)
+		// ---------------------------
+		// a pk of one integer column can be cut in intervals without browsing , the count is a
+		// template for the limit like the next query
+		if len(result.primaryKey) == 1 && isIntegerColumn(result.columnInfos, result.primaryKey[0]) {
+			pk_col := quoteIdentifier(result.primaryKey[0], tablequote)
+			where_first := ""
+			if len(sql_first_filter) > 0 {
+				where_first = " where " + sql_first_filter
+			}
+			q.query_for_browser_minmax = fmt.Sprintf("/* paradump */ select min(%s) , max(%s) from %s%s ", pk_col, pk_col, from, where_first)
+			q.query_for_reader_count = "/* paradump */ select count(*) from ( " + dialect.SelectWithLimit("1 as c", from, "( "+sql_cond_lower_pk+" ) and ( "+sql_cond_upper_pk+" )"+sql_next_filter, result.listColsPkOrderSQL, "%d") + " ) e "
+		}
+		return q
+	}
+	result.readQueries = read_queries(result.fullName)
+	if len(result.partitions) > 0 {
+		result.partition_queries = make(map[string]readQueries)
+		for _, p := range result.partitions {
+			result.partition_queries[p] = read_queries(result.fullName + " PARTITION (" + quoteIdentifier(p, tablequote) + ")")
+		}
+	}
+	result.param_indices_browser_next_qry = qry_indices_lo_bound
+	result.param_indices_equality_qry = qry_indices_equality
+	result.param_indices_interval_lo_qry = qry_indices_lo_bound
+	result.param_indices_interval_up_qry = qry_indices_up_bound
+	// ---------------------------
+	// the ranges are cut on the values of the first column of the pk when it is an integer
+	if len(result.primaryKey) > 0 && isIntegerColumn(result.columnInfos, result.primaryKey[0]) {
+		lead_col := quoteIdentifier(result.primaryKey[0], tablequote)
+		result.query_for_split_minmax = fmt.Sprintf("/* paradump */ select min(%s) , max(%s) from %s ", lead_col, lead_col, result.fullName)
+		result.query_for_split_dive = dialect.SelectWithLimit(result.listColsPkFetchSQL, result.fullName, lead_col+" >= "+sqlPlaceholder(dialect, 1), result.listColsPkOrderSQL, "1")
+	}
+	// ---------------------------
+	if mode_debug {
+		if len(enumPkCols) > 0 {
+			log.Printf(" for table %s we need to apdat query because of enum in pk.\n%s", result.fullName, result.query_for_browser_first)
+			log.Printf(" for table %s we need to apdat query because of enum in pk.\n%s", result.fullName, result.query_for_browser_next)
+		}
+	}
+	// ---------------------------------------------------------------------------------
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func populateDmlTemplateQuery(inf_t *metadataTable, dumpmode string, dumpinsertwithcol string, dstdriver string) {
+	if dumpmode == "cpy" {
+		if dstdriver == "postgres" {
+			inf_t.query_for_insert_head = fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES (", inf_t.dstDbName, inf_t.tbName, generateListCols4Sql(inf_t.columnInfos, ""))
+		} else {
+			if dstdriver == "mssql" {
+				inf_t.query_for_insert_head = fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES (", inf_t.dstDbName, inf_t.tbName, generateListCols4Sql(inf_t.columnInfos, ""))
+			} else {
+				inf_t.query_for_insert_head = fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES (", inf_t.dstDbName, inf_t.tbName, generateListCols4Sql(inf_t.columnInfos, "``"))
+			}
+		}
+	} else {
+		if dumpinsertwithcol == "full" || inf_t.projected {
+			inf_t.query_for_insert_head = fmt.Sprintf("INSERT INTO `%s`(%s) VALUES (", inf_t.tbName, generateListCols4Sql(inf_t.columnInfos, "``"))
+		} else {
+			inf_t.query_for_insert_head = fmt.Sprintf("INSERT INTO `%s` VALUES (", inf_t.tbName)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func getMetadataInfo4Tables(run_ctx context.Context, adbConn *sql.Conn, tableNames []aTable, guessPk bool, dumpmode string, dumpinsertwithcol string, srcdriver string, dstdriver string) ([]metadataTable, error) {
+	var result []metadataTable
+	for j := 0; j < len(tableNames); j++ {
+		info, m_err := getTableMetadataInfo(run_ctx, adbConn, tableNames[j].dbName, tableNames[j].tbName, tableNames[j].where, tableNames[j].columns, guessPk, dumpmode, dumpinsertwithcol, srcdriver, dstdriver)
+		if m_err != nil {
+			return nil, m_err
+		}
+		if dumpmode == "cpy" {
+			info.dstDbName = tableNames[j].dstDbName
+		}
+		populateDmlTemplateQuery(&info, dumpmode, dumpinsertwithcol, dstdriver)
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].fullName < result[j].fullName })
+	log.Printf("-------------------")
+	cnt := 0
+	for i, v := range result {
+		if v.onError&1 == 1 && !(v.onError&16 == 16) {
+			log.Printf("table %s.%s has no primary key\n", v.dbName, v.tbName)
+			log.Print("you may want to use -guessprimarykey\n")
+			cnt++
+		}
+		if v.onError&2 == 2 {
+			log.Printf("table %s.%s has no alternative indexes to use as a primary key\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&4 == 4 {
+			log.Printf("table %s.%s is not a innodb table\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&8 == 8 {
+			log.Printf("table %s.%s is not a regular table\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&16 == 16 {
+			log.Printf("table %s.%s does not exists\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if i-1 >= 0 && v.onError&16 == 0 && v.fullName == result[i-1].fullName {
+			log.Printf("table %s.%s is referenced twice\n", v.dbName, v.tbName)
+			cnt++
+		}
+	}
+	if cnt > 0 {
+		return nil, fmt.Errorf("too many ERRORS")
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].sizeBytes > result[j].sizeBytes })
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func checkTableOnDestination(run_ctx context.Context, driver string, adbConn *sql.Conn, a_table metadataTable) (string, bool, int, error) {
+	mode_debug := isDebug(run_ctx)
+	var dstinfo metadataTable
+	var m_err error
+	if driver == "mysql" {
+		dstinfo, m_err = getMysqlBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+	} else {
+		if driver == "mssql" {
+			dstinfo, m_err = getMsSqlBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+		} else {
+			dstinfo, m_err = getPostgresBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+		}
+	}
+	if m_err != nil {
+		return "", false, 0, m_err
+	}
+	if a_table.projected {
+		dstinfo.columnInfos = projectColumns(a_table.columnInfos, dstinfo.columnInfos)
+	}
+	res := reflect.DeepEqual(a_table.columnInfos, dstinfo.columnInfos)
+	err_msg := ""
+	cnt_empty := 0
+	if !res {
+		err_msg = fmt.Sprintf("columns definitions are not identical for %s", a_table.fullName)
+		if mode_debug {
+			log.Printf("src:\n%v\n", a_table.columnInfos)
+			log.Printf("dst:\n%v\n", dstinfo.columnInfos)
+		}
+	}
+	if driver != "postgres" {
+		if dstinfo.withTrigger {
+			err_msg = err_msg + fmt.Sprintf(" / table %s have triggers", a_table.fullName)
+		}
+	} else {
+		if mode_debug {
+			log.Printf("Table %s on destination will be populate without firing triggers", a_table.fullName)
+		}
+	}
+	if !dstinfo.isEmpty {
+		err_msg = err_msg + fmt.Sprintf(" / table %s is not empty", a_table.fullName)
+		cnt_empty++
+	}
+	return err_msg, err_msg != "", cnt_empty, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func populateDstSchema(lst_tab *[]aTable, lst_schemas []string, lst_dst_schemas []string) {
+	for i, s := range lst_schemas {
+		ds := lst_dst_schemas[i]
+		for j := 0; j < len(*lst_tab); j++ {
+			if (*lst_tab)[j].dbName == s {
+				(*lst_tab)[j].dstDbName = ds
+			}
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// create the directory of the files of each schema having a table to dump
+func makeSchemaDirs(lst_tab []aTable, dumpdir string, dumpfiletemplate string) error {
+	done := make(map[string]bool)
+	for _, v := range lst_tab {
+		if done[v.dbName] {
+			continue
+		}
+		done[v.dbName] = true
+		dname := dumpdir + strings.ReplaceAll(filepath.Dir(dumpfiletemplate), "%d", v.dbName)
+		err := os.MkdirAll(dname, 0o755)
+		if err != nil {
+			return fmt.Errorf("can not create directory %s\n%w", dname, err)
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// will fetch DDL informations on destination database , and compare table definitions
+func checkTablesOnDestination(run_ctx context.Context, srcdriver string, dstdriver string, adbConn *sql.Conn, infTables []metadataTable) error {
+	cnterr := 0
+	cntempty := 0
+	for n := range infTables {
+		msg, err, notempty, c_err := checkTableOnDestination(run_ctx, dstdriver, adbConn, infTables[n])
+		if c_err != nil {
+			return c_err
+		}
+		if err {
+			log.Printf("issue with table %s.%s on destination", infTables[n].dbName, infTables[n].tbName)
+			log.Printf("%s", msg)
+			cnterr++
+		}
+		cntempty = cntempty + notempty
+	}
+	if srcdriver != dstdriver && cntempty == 0 {
+		log.Printf("WARNING source is on %s and destination is on %s , i will assume that will work.", srcdriver, dstdriver)
+	} else {
+		if cnterr > 0 {
+			return fmt.Errorf("too many ERRORS")
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+// a browse unit is a table , one partition of a table , or one range of the pk of a table .
+// the chunks of a partition are read from the partition only , a range starts at begin_val
+// and ends before end_val ( at the end of the table when end_val is nil ) . unit_id is the
+// index of the unit in its table
+type browseunit struct {
+	table_id  int
+	unit_id   int
+	partition string
+	begin_val []string
+	end_val   []string
+}
+
+// the chunk ids of a table start at ( table_id + 1 ) * 10^12 , the ones of its n-th unit n *
+// 10^8 later , so the units of a table never share an id ( a table has less than 10^4 units ,
+// a unit less than 10^8 chunks )
+func (u browseunit) firstChunkId() int64 {
+	return (int64(u.table_id)+1)*1000000000000 + int64(u.unit_id)*100000000
+}
+
+// the browse units of a table , its partitions , its ranges or the whole table
+func browseUnits(a_table metadataTable, table_id int) []browseunit {
+	var units []browseunit
+	for _, p := range a_table.partitions {
+		units = append(units, browseunit{table_id: table_id, unit_id: len(units), partition: p})
+	}
+	for n := range a_table.ranges {
+		a_unit := browseunit{table_id: table_id, unit_id: len(units), begin_val: a_table.ranges[n]}
+		if n+1 < len(a_table.ranges) {
+			a_unit.end_val = a_table.ranges[n+1]
+		}
+		units = append(units, a_unit)
+	}
+	if len(units) == 0 {
+		units = append(units, browseunit{table_id: table_id})
+	}
+	return units
+}
+
+// the queries reading the whole table , or one of its partitions
+func (a_table *metadataTable) queries(partition string) readQueries {
+	if len(partition) == 0 {
+		return a_table.readQueries
+	}
+	return a_table.partition_queries[partition]
+}
+
+// ------------------------------------------------------------------------------------------
+// split_limit is set for a chunk of an arithmetic browse that may have too many rows , the
+// reader cuts it in halves until each part has at most split_limit rows
+type tablechunk struct {
+	table_id        int
+	partition       string
+	chunk_id        int64
+	is_done         bool
+	begin_val       []string
+	end_val         []string
+	begin_equal_end bool
+	split_limit     int64
+}
+
+type datachunk struct {
+	table_id int
+	usedlen  int
+	chunk_id int64
+	rows     []*rowchunk
+}
+
+// ------------------------------------------------------------------------------------------
+type insertchunk struct {
+	table_id int
+	chunk_id int64
+	rows_cnt int
+	sql      *string
+	params   *[]any
+}
+
+// the bytes of the statement ( or of the text ) and of its parameters
+func (c insertchunk) size() int64 {
+	ret_val := int64(len(*c.sql))
+	if c.params != nil {
+		for _, p := range *c.params {
+			if s, ok := p.(string); ok {
+				ret_val += int64(len(s))
+			}
+		}
+	}
+	return ret_val
+}
+
+// ------------------------------------------------------------------------------------------
+func tableChunkBrowser(ctx_root context.Context, adbConn *sql.Conn, id int, tableidstoscan chan browseunit, tableInfos []metadataTable, chunk2read chan tablechunk, sizeofchunk_init int64) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableChunkBrowser [%02d] start\n", id)
+	}
+	var sizeofchunk int64
+	var must_prepare_query bool
+
+	ctx, cancel := phaseContext(ctx_root, timeout_connect, 2*time.Second)
+	p_err := adbConn.PingContext(ctx)
+	cancel()
+	if p_err != nil {
+		if ctx_root.Err() != nil {
+			return
+		}
+		failf("can not ping\n%w", phaseError(ctx, p_err, ""))
+	}
+	format_cnt_table := " %0" + fmt.Sprintf("%d", len(fmt.Sprintf("%d", len(tableInfos)))) + "d "
+	for {
+		var unit browseunit
+		select {
+		case <-ctx_root.Done():
+			unit.table_id = -1
+		case unit = <-tableidstoscan:
+		}
+		if unit.table_id == -1 {
+			break
+		}
+		j := unit.table_id
+		unit_name := tableInfos[j].fullName
+		if len(unit.partition) > 0 {
+			unit_name = unit_name + " partition " + unit.partition
+		}
+		if unit.begin_val != nil {
+			unit_name = fmt.Sprintf("%s range %s", unit_name, unit.begin_val)
+		}
+		if tableInfos[j].arithmetic && tableChunkArithmetic(ctx_root, adbConn, unit, unit_name, tableInfos, chunk2read, sizeofchunk_init) {
+			if ctx_root.Err() != nil {
+				return
+			}
+			tableInfos[j].deferred.done()
+			continue
+		}
+		sizeofchunk = sizeofchunk_init
+		unit_queries := tableInfos[j].queries(unit.partition)
+		the_first_query := unit_queries.query_for_browser_first
+		the_next_query := unit_queries.query_for_browser_next
+		var sql_vals_first []any
+		var sql_vals_end []any
+		if unit.begin_val != nil {
+			the_first_query = unit_queries.query_for_browser_first_lower
+			sql_vals_first = generateValuesForPredicat(tableInfos[j].param_indices_interval_lo_qry, unit.begin_val)
+		}
+		if unit.end_val != nil {
+			the_first_query = unit_queries.query_for_browser_first_range
+			the_next_query = unit_queries.query_for_browser_next_range
+			sql_vals_end = generateValuesForPredicat(tableInfos[j].param_indices_interval_up_qry, unit.end_val)
+			sql_vals_first = append(sql_vals_first, sql_vals_end...)
+		}
+		if mode_debug {
+			log.Printf("table %s size pk %d query :  %s \n", unit_name, tableInfos[j].cntPkCols, the_first_query)
+		}
+		ctx, cancel = phaseContext(ctx_root, timeout_browse, 16*time.Second)
+		q_rows, q_err := adbConn.QueryContext(ctx, the_first_query, sql_vals_first...)
+		if q_err != nil {
+			cancel()
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not query table %s to get first pk aka ( %s )\n%w", tableInfos[j].fullName, tableInfos[j].listColsPkSQL, phaseError(ctx, q_err, tableInfos[j].fullName))
+		}
+		a_sql_row := make([]*sql.NullString, tableInfos[j].cntPkCols)
+		var pk_cnt int64
+		ptrs := make([]any, tableInfos[j].cntPkCols)
+		var ptrs_nextpk []any
+		if tableInfos[j].fakePrimaryKey {
+			ptrs_nextpk = make([]any, tableInfos[j].cntPkCols+1)
+		} else {
+			ptrs_nextpk = make([]any, tableInfos[j].cntPkCols)
+		}
+		for i := range a_sql_row {
+			ptrs[i] = &a_sql_row[i]
+			ptrs_nextpk[i] = &a_sql_row[i]
+		}
+		if tableInfos[j].fakePrimaryKey {
+			ptrs_nextpk[tableInfos[j].cntPkCols] = &pk_cnt
+		} else {
+			pk_cnt = -1
+		}
+		row_cnt := 0
+		for q_rows.Next() {
+			err := q_rows.Scan(ptrs...)
+			if err != nil {
+				failf("can not scan result for table %s\n%w", tableInfos[j].fullName, err)
+			}
+			row_cnt++
+		}
+		r_err := q_rows.Err()
+		cancel()
+		if r_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not read first pk of table %s\n%w", tableInfos[j].fullName, phaseError(ctx, r_err, tableInfos[j].fullName))
+		}
+		if row_cnt == 0 {
+			log.Printf("table["+format_cnt_table+"] %s is empty \n", j, unit_name)
+			tableInfos[j].deferred.done()
+			continue
+		}
+		start_pk_row := make([]string, tableInfos[j].cntPkCols)
+		for n, value := range a_sql_row {
+			start_pk_row[n] = value.String
+		}
+		log.Printf("table["+format_cnt_table+"] %s first pk ( %s ) - start scan pk %s\n", j, unit_name, tableInfos[j].listColsPkSQL, start_pk_row)
+		// --------------------------------------------------------------------------
+		var the_finish_query string
+		var prepare_finish_query *sql.Stmt
+		var p_err error
+		var end_pk_row []string
+		var chunk_id int64 = unit.firstChunkId()
+		// --------------------------------------------------------------------------
+		end_pk_row = start_pk_row
+		sizeofchunk = math.MaxInt
+		// --------------------------------------------------------------------------
+		for {
+			start_pk_row = end_pk_row
+			begin_equal_end := false
+			if chunk_target := tableInfos[j].sizer.size(sizeofchunk_init); sizeofchunk != chunk_target {
+				must_prepare_query = true
+				sizeofchunk = chunk_target
+			}
+			for {
+				// ----------------------------------------------------------
+				if must_prepare_query {
+					the_finish_query = fmt.Sprintf(the_next_query, sizeofchunk)
+					if prepare_finish_query != nil {
+						_ = prepare_finish_query.Close()
+					}
+					ctx, cancel = phaseContext(ctx_root, timeout_browse, 2*time.Second)
+					prepare_finish_query, p_err = adbConn.PrepareContext(ctx, the_finish_query)
+					cancel()
+					if p_err != nil {
+						if ctx_root.Err() != nil {
+							return
+						}
+						failf("can not prepare to get next pk ( %s )\n%w", the_finish_query, phaseError(ctx, p_err, tableInfos[j].fullName))
+					}
+					must_prepare_query = false
+				}
+				// ----------------------------------------------------------
+				sql_vals_pk := generateValuesForPredicat(tableInfos[j].param_indices_browser_next_qry, start_pk_row)
+				sql_vals_pk = append(sql_vals_pk, sql_vals_end...)
+				ctx, cancel = phaseContext(ctx_root, timeout_browse, 0)
+				q_rows, q_err = prepare_finish_query.QueryContext(ctx, sql_vals_pk...)
+				if q_err != nil {
+					cancel()
+					if ctx_root.Err() != nil {
+						return
+					}
+					failf("can not query table %s to get next pk aka ( %s )\n%w", tableInfos[j].fullName, tableInfos[j].listColsPkSQL, phaseError(ctx, q_err, tableInfos[j].fullName))
+				}
+				for q_rows.Next() {
+					err := q_rows.Scan(ptrs_nextpk...)
+					if err != nil {
+						log.Printf("can not scan result for table %s doing get next pk ( len ptrs_nextpk %d ) ( len sql_vals_pk %d) \n", tableInfos[j].fullName, len(ptrs_nextpk), len(sql_vals_pk))
+						log.Printf("query for nextpk is %s", the_finish_query)
+						fail(err)
+					}
+				}
+				r_err = q_rows.Err()
+				cancel()
+				if r_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					failf("can not read next pk of table %s\n%w", tableInfos[j].fullName, phaseError(ctx, r_err, tableInfos[j].fullName))
+				}
+				end_pk_row = make([]string, tableInfos[j].cntPkCols)
+				for n := range a_sql_row {
+					end_pk_row[n] = a_sql_row[n].String
+				}
+				if mode_debug {
+					log.Printf("tableChunkBrowser: table %s query :  %s \n with %d params\nval for query: %s\nresult: %s\n", tableInfos[j].fullName, the_finish_query, len(sql_vals_pk), sql_vals_pk, end_pk_row)
+					log.Printf("tableChunkBrowser: table %s pk interval : %s -> %s\n", tableInfos[j].fullName, start_pk_row, end_pk_row)
+					log.Printf("tableChunkBrowser: %t\n", reflect.DeepEqual(start_pk_row, end_pk_row))
+				}
+				begin_equal_end = reflect.DeepEqual(start_pk_row, end_pk_row)
+				if tableInfos[j].fakePrimaryKey && begin_equal_end && pk_cnt >= sizeofchunk {
+					sizeofchunk = sizeofchunk * 15 / 10
+					must_prepare_query = true
+				} else {
+					break
+				}
+				// ----------------------------------------------------------
+			}
+			// ------------------------------------------------------------------
+			chunk_id++
+			var a_chunk tablechunk
+			a_chunk.table_id = j
+			a_chunk.partition = unit.partition
+			a_chunk.chunk_id = chunk_id
+			a_chunk.begin_val = start_pk_row
+			a_chunk.end_val = end_pk_row
+			a_chunk.begin_equal_end = begin_equal_end
+			a_chunk.is_done = false
+			tableInfos[j].deferred.add()
+			select {
+			case <-ctx_root.Done():
+				return
+			case chunk2read <- a_chunk:
+			}
+			// ------------------------------------------------------------------
+			if begin_equal_end {
+				break
+			}
+		}
+		log.Printf("table["+format_cnt_table+"] %s scan is done , pk col ( %s ) scan size pk %d last pk %s\n", j, unit_name, tableInfos[j].listColsPkSQL, pk_cnt, end_pk_row)
+		tableInfos[j].deferred.done()
+		// --------------------------------------------------------------------------
+		if prepare_finish_query != nil {
+			_ = prepare_finish_query.Close()
+		}
+		// --------------------------------------------------------------------------
+	}
+	if mode_debug {
+		log.Printf("tableChunkBrowser [%02d] finish\b", id)
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+// cut the pk of a big table in cnt ranges browsed in parallel , the bounds are the first pk
+// of the table and the first pk found at regular values of the first column of the pk ( an
+// index dive for each one ) . nil when the table can not be cut
+func getBrowseRanges(run_ctx context.Context, adbConn *sql.Conn, a_table *metadataTable, cnt int) ([][]string, error) {
+	scan_pk := func(query string, args ...any) ([]string, error) {
+		a_sql_row := make([]sql.NullString, a_table.cntPkCols)
+		ptrs := make([]any, a_table.cntPkCols)
+		for i := range a_sql_row {
+			ptrs[i] = &a_sql_row[i]
+		}
+		ctx, cancel := phaseContext(run_ctx, timeout_metadata, 16*time.Second)
+		defer cancel()
+		q_err := adbConn.QueryRowContext(ctx, query, args...).Scan(ptrs...)
+		if q_err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if q_err != nil {
+			return nil, fmt.Errorf("can not get a pk of table %s to cut it in ranges\n%w", a_table.fullName, phaseError(ctx, q_err, a_table.fullName))
+		}
+		ret_val := make([]string, a_table.cntPkCols)
+		for n, value := range a_sql_row {
+			ret_val[n] = value.String
+		}
+		return ret_val, nil
+	}
+	// ----------------------------------------------------------------------------------
+	first_pk, s_err := scan_pk(a_table.query_for_browser_first)
+	if first_pk == nil {
+		return nil, s_err
+	}
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 16*time.Second)
+	var min_lead, max_lead sql.NullString
+	q_err := adbConn.QueryRowContext(ctx, a_table.query_for_split_minmax).Scan(&min_lead, &max_lead)
+	cancel()
+	if q_err != nil {
+		return nil, fmt.Errorf("can not get min and max of %s of table %s\n%w", a_table.primaryKey[0], a_table.fullName, phaseError(ctx, q_err, a_table.fullName))
+	}
+	lo, l_err := strconv.ParseInt(min_lead.String, 10, 64)
+	hi, h_err := strconv.ParseInt(max_lead.String, 10, 64)
+	if l_err != nil || h_err != nil {
+		log.Printf("table %s is not cut in ranges , %s from %s to %s does not fit in an int64 , one browser reads it", a_table.fullName, a_table.primaryKey[0], min_lead.String, max_lead.String)
+		return nil, nil
+	}
+	// ----------------------------------------------------------------------------------
+	// consecutive dives can find the same pk when the values are sparse
+	result := [][]string{first_pk}
+	step := (uint64(hi) - uint64(lo)) / uint64(cnt)
+	for i := 1; i < cnt; i++ {
+		a_pk, s_err := scan_pk(a_table.query_for_split_dive, lo+int64(step*uint64(i)))
+		if s_err != nil {
+			return nil, s_err
+		}
+		if a_pk == nil || reflect.DeepEqual(a_pk, result[len(result)-1]) {
+			continue
+		}
+		result = append(result, a_pk)
+	}
+	if len(result) < 2 {
+		log.Printf("table %s is not cut in ranges , every dive found its first pk , one browser reads it", a_table.fullName)
+		return nil, nil
+	}
+	log.Printf("table %s is browsed in %d ranges of its pk %s , from %s", a_table.fullName, len(result), a_table.listColsPkSQL, result)
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// browse a pk of one integer column without browse queries , min and max are read once and
+// the interval is cut in chunks of a fixed width . the width is the size of a chunk scaled by
+// the estimated density of the table , a chunk wider than twice the size of a chunk may have
+// too many rows and is checked by the reader . false when the pk does not fit in an int64
+func tableChunkArithmetic(ctx_root context.Context, adbConn *sql.Conn, unit browseunit, unit_name string, tableInfos []metadataTable, chunk2read chan tablechunk, sizeofchunk int64) bool {
+	j := unit.table_id
+	ctx, cancel := phaseContext(ctx_root, timeout_browse, 16*time.Second)
+	var min_pk, max_pk sql.NullString
+	q_err := adbConn.QueryRowContext(ctx, tableInfos[j].queries(unit.partition).query_for_browser_minmax).Scan(&min_pk, &max_pk)
+	cancel()
+	if q_err != nil {
+		if ctx_root.Err() != nil {
+			return true
+		}
+		failf("can not query table %s to get min and max pk aka ( %s )\n%w", unit_name, tableInfos[j].listColsPkSQL, phaseError(ctx, q_err, tableInfos[j].fullName))
+	}
+	if !min_pk.Valid {
+		log.Printf("table %s is empty \n", unit_name)
+		return true
+	}
+	lo, l_err := strconv.ParseInt(min_pk.String, 10, 64)
+	hi, h_err := strconv.ParseInt(max_pk.String, 10, 64)
+	if l_err != nil || h_err != nil {
+		log.Printf("table %s pk ( %s ) from %s to %s does not fit in an int64 , it is browsed", unit_name, tableInfos[j].listColsPkSQL, min_pk.String, max_pk.String)
+		return false
+	}
+	// --------------------------------------------------------------------------
+	// the rows of a partition are estimated as a share of the rows of the table
+	est_rows := tableInfos[j].cntRows
+	if len(unit.partition) > 0 {
+		est_rows = est_rows / int64(len(tableInfos[j].partitions))
+	}
+	width := sizeofchunk
+	span := float64(hi) - float64(lo) + 1
+	if est_rows > 0 && span > float64(est_rows) {
+		width = int64(math.Min(float64(sizeofchunk)*span/float64(est_rows), math.MaxInt64/2))
+	}
+	var split_limit int64
+	if width > 2*sizeofchunk {
+		split_limit = 2 * sizeofchunk
+	}
+	log.Printf("table %s pk ( %s ) from %d to %d is cut in chunks of width %d\n", unit_name, tableInfos[j].listColsPkSQL, lo, hi, width)
+	// --------------------------------------------------------------------------
+	// intervals exclude their end , the last chunk is the max alone . the distances are
+	// computed in uint64 , they can be larger than an int64
+	var chunk_id int64 = unit.firstChunkId()
+	start := lo
+	for {
+		chunk_id++
+		a_chunk := tablechunk{table_id: j, partition: unit.partition, chunk_id: chunk_id}
+		if start == hi {
+			a_chunk.begin_val = []string{strconv.FormatInt(hi, 10)}
+			a_chunk.end_val = a_chunk.begin_val
+			a_chunk.begin_equal_end = true
+		} else {
+			end := hi
+			if uint64(hi)-uint64(start) > uint64(width) {
+				end = start + width
+			}
+			a_chunk.begin_val = []string{strconv.FormatInt(start, 10)}
+			a_chunk.end_val = []string{strconv.FormatInt(end, 10)}
+			a_chunk.split_limit = split_limit
+			start = end
+		}
+		tableInfos[j].deferred.add()
+		select {
+		case <-ctx_root.Done():
+			return true
+		case chunk2read <- a_chunk:
+		}
+		if a_chunk.begin_equal_end {
+			break
+		}
+	}
+	log.Printf("table %s scan is done , %d chunks\n", unit_name, chunk_id-unit.firstChunkId())
+	return true
+}
+
+// ------------------------------------------------------------------------------------------
+// cut a chunk of an arithmetic browse in halves until each part has at most split_limit
+// rows , the parts are in the order of the pk . an interval not wider than split_limit is
+// not counted . nil when the dump is cancelled
+func splitArithmeticChunk(ctx_root context.Context, adbConn *sql.Conn, a_table *metadataTable, a_chunk tablechunk) []tablechunk {
+	mode_debug := isDebug(ctx_root)
+	var result []tablechunk
+	todo := []tablechunk{a_chunk}
+	for len(todo) > 0 {
+		a_part := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		lo, _ := strconv.ParseInt(a_part.begin_val[0], 10, 64)
+		hi, _ := strconv.ParseInt(a_part.end_val[0], 10, 64)
+		if uint64(hi)-uint64(lo) <= uint64(a_part.split_limit) {
+			result = append(result, a_part)
+			continue
+		}
+		sql_vals_pk := generateValuesForPredicat(a_table.param_indices_interval_lo_qry, a_part.begin_val)
+		sql_vals_pk = append(sql_vals_pk, generateValuesForPredicat(a_table.param_indices_interval_up_qry, a_part.end_val)...)
+		the_query := fmt.Sprintf(a_table.queries(a_part.partition).query_for_reader_count, a_part.split_limit+1)
+		var cnt_rows int64
+		ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 0)
+		q_err := adbConn.QueryRowContext(ctx, the_query, sql_vals_pk...).Scan(&cnt_rows)
+		cancel()
+		if q_err != nil {
+			if ctx_root.Err() != nil {
+				return nil
+			}
+			failf("can not count the rows of chunk %d of table %s\n%w", a_part.chunk_id, a_table.fullName, phaseError(ctx, q_err, a_table.fullName))
+		}
+		if cnt_rows <= a_part.split_limit {
+			result = append(result, a_part)
+			continue
+		}
+		mid := strconv.FormatInt(lo+int64((uint64(hi)-uint64(lo))/2), 10)
+		if mode_debug {
+			log.Printf("table %s chunk %12d %s -> %s is split at %s\n", a_table.fullName, a_part.chunk_id, a_part.begin_val, a_part.end_val, mid)
+		}
+		left, right := a_part, a_part
+		left.end_val = []string{mid}
+		right.begin_val = []string{mid}
+		todo = append(todo, right, left)
+		a_table.deferred.add()
+	}
+	return result
+}
+
+// ------------------------------------------------------------------------------------------
+func chunkReaderDumpHeader(dumpmode string, cur_iow io.Writer, sql_tab_cols string) {
+	if dumpmode == "sql" {
+		io.WriteString(cur_iow, "SET NAMES utf8mb4;\n")
+		io.WriteString(cur_iow, "SET TIME_ZONE='+00:00';\n")
+	}
+	if dumpmode == "csv" {
+		io.WriteString(cur_iow, fmt.Sprintf("%s\n", sql_tab_cols))
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// return the count of rows , the bytes of the values read and the time waiting for the
+// generators
+func chunkReaderDumpProcess(ctx_root context.Context, threadid int, q_rows *sql.Rows, a_table_info *metadataTable, tab_id int, chk_id int64, chan2gen chan datachunk) (int, int64, time.Duration) {
+	mode_debug := isDebug(ctx_root)
+	// --------------------------------------------------------------------------
+	var a_dta_chunk datachunk
+	a_dta_chunk = datachunk{usedlen: 0, chunk_id: chk_id, table_id: tab_id, rows: make([]*rowchunk, a_table_info.insert_size)}
+	row_cnt := 0
+	all_cnt := 0
+	var all_bytes int64
+	var wait_time time.Duration
+	if mode_debug {
+		log.Printf("chunkReaderDumpProcess [%02d] table %03d chunk %12d \n", threadid, tab_id, chk_id)
+	}
+	ptrs := make([]any, a_table_info.cntCols)
+	for q_rows.Next() {
+		var a_simple_row rowchunk
+		a_simple_row.cols = make([]sql.NullString, a_table_info.cntCols)
+		// ------------------------------------------------------------------
+		for i := 0; i < a_table_info.cntCols; i++ {
+			ptrs[i] = &a_simple_row.cols[i]
+		}
+		// ------------------------------------------------------------------
+		err := q_rows.Scan(ptrs...)
+		if err != nil {
+			log.Printf("can not scan %s ( already scan %d ) , len(ptrs) = %d ", a_table_info.tbName, row_cnt, len(ptrs))
+			fail(err)
+		}
+		for i := range a_simple_row.cols {
+			all_bytes += int64(len(a_simple_row.cols[i].String))
+		}
+		// ------------------------------------------------------------------
+		a_dta_chunk.rows[row_cnt] = &a_simple_row
+		row_cnt++
+		all_cnt++
+		// ------------------------------------------------------------------
+		if row_cnt >= a_table_info.insert_size {
+			a_dta_chunk.usedlen = row_cnt
+			a_table_info.deferred.add()
+			wait_start := time.Now()
+			select {
+			case <-ctx_root.Done():
+				return all_cnt, all_bytes, wait_time
+			case chan2gen <- a_dta_chunk:
+			}
+			wait_time += time.Since(wait_start)
+			row_cnt = 0
+			a_dta_chunk = datachunk{usedlen: 0, table_id: tab_id, chunk_id: chk_id, rows: make([]*rowchunk, a_table_info.insert_size)}
+		}
+	}
+	if row_cnt > 0 {
+		a_dta_chunk.usedlen = row_cnt
+		a_table_info.deferred.add()
+		wait_start := time.Now()
+		select {
+		case <-ctx_root.Done():
+			return all_cnt, all_bytes, wait_time
+		case chan2gen <- a_dta_chunk:
+		}
+		wait_time += time.Since(wait_start)
+	}
+	a_table_info.deferred.done()
+	return all_cnt, all_bytes, wait_time
+}
+
+// ------------------------------------------------------------------------------------------
+type cacheTableChunkReader struct {
+	table_id               int
+	partition              string
+	lastusagecnt           int
+	fullname               string
+	indices_lo_pk          []int
+	indices_up_pk          []int
+	indices_equal_pk       []int
+	interval_query         string
+	equality_query         string
+	interval_prepared_stmt *sql.Stmt
+	equality_prepared_stmt *sql.Stmt
+}
+
+// ------------------------------------------------------------------------------------------
+func tableChunkReader(ctx_root context.Context, chunk2read chan tablechunk, chan2generator chan datachunk, adbConn *sql.Conn, tableInfos []metadataTable, id int, cntBrowser int) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableChunkReader[%02d] start\n", id)
+	}
+	var cntreadchunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabReadingVars []*cacheTableChunkReader
+
+	var last_table *cacheTableChunkReader
+
+	tabReadingVars = make([]*cacheTableChunkReader, cntBrowser+1)
+
+	// the parts of a chunk split by the reader are read before the next chunk
+	var parts []tablechunk
+
+	for {
+		var a_chunk tablechunk
+		if len(parts) > 0 {
+			a_chunk, parts = parts[0], parts[1:]
+		} else {
+			select {
+			case <-ctx_root.Done():
+				a_chunk.is_done = true
+			case a_chunk = <-chunk2read:
+			}
+			if a_chunk.is_done {
+				break
+			}
+			if a_chunk.split_limit > 0 {
+				parts = splitArithmeticChunk(ctx_root, adbConn, &tableInfos[a_chunk.table_id], a_chunk)
+				if len(parts) == 0 {
+					return
+				}
+				a_chunk, parts = parts[0], parts[1:]
+			}
+		}
+		cntreadchunk++
+
+		if last_table == nil || last_table.table_id != a_chunk.table_id || last_table.partition != a_chunk.partition {
+			// ------------------------------------------------------------------
+			var tab_found int = -1
+			var empty_slot int = -1
+			var lru_slot int = -1
+			var lru_val int = math.MaxInt
+			for n := range tabReadingVars {
+				if tabReadingVars[n] == nil {
+					empty_slot = n
+				} else if tabReadingVars[n].table_id == a_chunk.table_id && tabReadingVars[n].partition == a_chunk.partition {
+					tab_found = n
+					break
+				} else {
+					if tabReadingVars[n].lastusagecnt < lru_val {
+						lru_val = tabReadingVars[n].lastusagecnt
+						lru_slot = n
+					}
+				}
+			}
+			if tab_found == -1 {
+				cache_miss++
+				// ----------------------------------------------------------
+				if empty_slot == -1 {
+					tabReadingVars[lru_slot].interval_prepared_stmt.Close()
+					tabReadingVars[lru_slot].equality_prepared_stmt.Close()
+					tabReadingVars[lru_slot] = nil
+					empty_slot = lru_slot
+				}
+				// ----------------------------------------------------------
+				var new_info cacheTableChunkReader
+
+				new_info.table_id = a_chunk.table_id
+				new_info.partition = a_chunk.partition
+				new_info.lastusagecnt = cntreadchunk
+
+				new_info.fullname = tableInfos[a_chunk.table_id].fullName
+				new_info.interval_query = tableInfos[a_chunk.table_id].queries(a_chunk.partition).query_for_reader_interval
+				new_info.equality_query = tableInfos[a_chunk.table_id].queries(a_chunk.partition).query_for_reader_equality
+				new_info.indices_lo_pk = tableInfos[a_chunk.table_id].param_indices_interval_lo_qry
+				new_info.indices_up_pk = tableInfos[a_chunk.table_id].param_indices_interval_up_qry
+				new_info.indices_equal_pk = tableInfos[a_chunk.table_id].param_indices_equality_qry
+				// ------------------------------------------------------------------
+				ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				var p_err error
+				new_info.interval_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.interval_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableChunkReader[%02d] finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a chunk ( %s )\n%w", new_info.interval_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				ctx, cancel = phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				new_info.equality_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.equality_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableChunkReader[%02d] finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a chunk of one pk ( %s )\n%w", new_info.equality_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				// ------------------------------------------------------------------
+				tabReadingVars[empty_slot] = &new_info
+				last_table = &new_info
+			} else {
+				cache_hit++
+				last_table = tabReadingVars[tab_found]
+				last_table.lastusagecnt = cntreadchunk
+			}
+		} else {
+			last_hit++
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("tableChunkReader[%02d] table %03d chunk %12d %s -> %s\n", id, a_chunk.table_id, a_chunk.chunk_id, a_chunk.begin_val, a_chunk.end_val)
+		}
+		// --------------------------------------------------------------------------
+		var sql_vals_pk []any
+		var the_query *string
+		var prepared_query *sql.Stmt
+		if a_chunk.begin_equal_end {
+			sql_vals_pk = generateValuesForPredicat(last_table.indices_equal_pk, a_chunk.begin_val)
+			the_query = &last_table.equality_query
+			prepared_query = last_table.equality_prepared_stmt
+		} else {
+			sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.begin_val)
+			sql_vals_pk = append(sql_vals_pk, generateValuesForPredicat(last_table.indices_up_pk, a_chunk.end_val)...)
+			the_query = &last_table.interval_query
+			prepared_query = last_table.interval_prepared_stmt
+		}
+		start := time.Now()
+		tableInfos[last_table.table_id].progress.startChunk()
+		ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 0)
+		q_rows, q_err := prepared_query.QueryContext(ctx, sql_vals_pk...)
+		if q_err != nil {
+			cancel()
+			if ctx_root.Err() != nil {
+				return
+			}
+			log.Printf("table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, a_chunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("ind lo: %v  ind up: %v", last_table.indices_lo_pk, last_table.indices_up_pk)
+			failf("can not query table %s to read the chunks\n%w", last_table.fullname, phaseError(ctx, q_err, last_table.fullname))
+		}
+		if mode_debug {
+			log.Printf("table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, a_chunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("ind lo: %v  ind up: %v", last_table.indices_lo_pk, last_table.indices_up_pk)
+		}
+		// --------------------------------------------------------------------------
+		cnt_rows, cnt_bytes, wait_time := chunkReaderDumpProcess(ctx_root, id, q_rows, &tableInfos[last_table.table_id], last_table.table_id, a_chunk.chunk_id, chan2generator)
+		r_err := q_rows.Err()
+		cancel()
+		if r_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not read chunk %d of table %s\n%w", a_chunk.chunk_id, last_table.fullname, phaseError(ctx, r_err, last_table.fullname))
+		}
+		tableInfos[last_table.table_id].progress.endChunk(cnt_rows, cnt_bytes)
+		// the chunks of one pk are not cut by the browser , they do not say what size to cut .
+		// the time waiting for the generators is not the time to read
+		if !a_chunk.begin_equal_end {
+			tableInfos[last_table.table_id].sizer.record(cnt_rows, cnt_bytes, time.Since(start)-wait_time)
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("table %s chunk query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, *the_query, len(sql_vals_pk), sql_vals_pk)
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+	for n := range tabReadingVars {
+		if tabReadingVars[n] != nil {
+			tabReadingVars[n].interval_prepared_stmt.Close()
+			tabReadingVars[n].equality_prepared_stmt.Close()
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Printf("tableChunkReader[%02d] finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+		log.Printf("tableChunkReader[%02d] finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func generateListPkColsFetch4Sql(col_pk []string, enumCols []string, tablequote string) string {
+	if len(col_pk) == 0 {
+		return ""
+	}
+	a_str := ""
+	for ctab := 0; ctab < len(col_pk); ctab++ {
+		col_is_enum := false
+		for e := range enumCols {
+			if enumCols[e] == col_pk[ctab] {
+				col_is_enum = true
+				break
+			}
+		}
+		if ctab > 0 {
+			a_str = a_str + ","
+		}
+		if col_is_enum {
+			a_str = a_str + " cast(" + quoteIdentifier(col_pk[ctab], tablequote) + " as unsigned integer ) as " + quoteIdentifier(col_pk[ctab], tablequote)
+		} else {
+			a_str = a_str + " " + quoteIdentifier(col_pk[ctab], tablequote) + " "
+		}
+	}
+	return a_str
+}
+
+// ------------------------------------------------------------------------------------------
+type cachedataChunkGenerator struct {
+	table_id     int
+	lastusagecnt int
+	buf_arr      []colchunk
+	init_size    int
+	pad_row_size int
+	tab_meta     *metadataTable
+}
+
+// ------------------------------------------------------------------------------------------
+func dataChunkGeneratorCpy(ctx_root context.Context, rowvalueschan chan datachunk, id int, tableInfos []metadataTable, sql2inject chan insertchunk, dst_driver string, cntBrowser int) {
+	mode_debug := isDebug(ctx_root)
+	mode_trace := isTrace(ctx_root)
+	// ----------------------------------------------------------------------------------
+	// we use a array of string to generate the final sql insert
+	//
+	//   row 1 |   insert into (   | col1  | , |  col2 | , | ....   | coln
+	//   row 2 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   row 3 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   row 4 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   end   |   ) ; \n
+	//
+	//    4 rows with n cols => 4*n*2 + 1
+	//
+	//  echo row need 1           prefix
+	//                cntCols     for value
+	//                cntCols - 1 for coma
+	//  and a final   suffix
+	//
+	//  total cells => insert_size x ( tab_meta.cntCols * 2 ) cells + 1
+	//
+	// ----------------------------------------------------------------------------------
+	nullStr := "NULL"
+	betwStr := "),("
+	endStr := ");\n"
+
+	var cntgenechunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabGenVars []*cachedataChunkGenerator
+	var lastTable *cachedataChunkGenerator
+
+	tabGenVars = make([]*cachedataChunkGenerator, cntBrowser+1)
+
+	// ----------------------------------------------------------------------------------
+	for {
+		var a_dta_chunk datachunk
+		select {
+		case <-ctx_root.Done():
+			a_dta_chunk.table_id = -1
+		case a_dta_chunk = <-rowvalueschan:
+		}
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d usedlen %5d", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id, a_dta_chunk.usedlen)
+		}
+		if a_dta_chunk.table_id == -1 {
+			break
+		}
+		cntgenechunk++
+
+		if lastTable != nil && lastTable.table_id == a_dta_chunk.table_id {
+			last_hit++
+		} else {
+			// ------------------------------------------------------------------
+			var tab_found int = -1
+			var empty_slot int = -1
+			var lru_slot int = -1
+			var lru_val int = math.MaxInt
+			for n := range tabGenVars {
+				if tabGenVars[n] == nil {
+					empty_slot = n
+				} else if tabGenVars[n].table_id == a_dta_chunk.table_id {
+					tab_found = n
+					break
+				} else {
+					if tabGenVars[n].lastusagecnt < lru_val {
+						lru_val = tabGenVars[n].lastusagecnt
+						lru_slot = n
+					}
+				}
+			}
+			if tab_found > -1 {
+				cache_hit++
+				lastTable = tabGenVars[tab_found]
+				lastTable.lastusagecnt = cntgenechunk
+				cache_miss++
+			} else {
+				if empty_slot == -1 {
+					empty_slot = lru_slot
+				}
+				var new_info cachedataChunkGenerator
+				tabGenVars[empty_slot] = &new_info
+				lastTable = &new_info
+				// ----------------------------------------------------------
+				lastTable.table_id = a_dta_chunk.table_id
+				// ----------------------------------------------------------
+				lastTable.tab_meta = &tableInfos[lastTable.table_id]
+				// ----------------------------------------------------------
+				lastTable.buf_arr = make([]colchunk, lastTable.tab_meta.insert_size*2*lastTable.tab_meta.cntCols+1)
+				// ----------------------------------------------------------
+				u_ind := 0
+				coma_str := ","
+				pad_beg_size := 0
+				pad_mid_size := 0 //  will be repeat =>  cnt rows -1
+				pad_end_size := 0
+				// ----------------------------------------------------------
+				// row 1
+				lastTable.buf_arr[u_ind].val = &lastTable.tab_meta.query_for_insert_head
+				lastTable.buf_arr[u_ind].kind = 0
+				pad_beg_size += len(lastTable.tab_meta.query_for_insert_head)
+				u_ind += 2
+				for c := 1; c < lastTable.tab_meta.cntCols; c++ {
+					lastTable.buf_arr[u_ind].val = &coma_str
+					lastTable.buf_arr[u_ind].kind = 0
+					pad_beg_size += 1
+					u_ind += 2
+				}
+				if lastTable.tab_meta.insert_size > 1 {
+					// --------------------------------------------------
+					// row 2
+					lastTable.buf_arr[u_ind].val = &betwStr
+					lastTable.buf_arr[u_ind].kind = 0
+					pad_mid_size += len(betwStr)
+					u_ind += 2
+					for c := 1; c < lastTable.tab_meta.cntCols; c++ {
+						lastTable.buf_arr[u_ind].val = &coma_str
+						lastTable.buf_arr[u_ind].kind = 0
+						pad_mid_size += 1
+						u_ind += 2
+					}
+					// --------------------------------------------------
+					for r := 2; r < lastTable.tab_meta.insert_size; r++ {
+						for c := 0; c < lastTable.tab_meta.cntCols; c++ {
+							r_ind := (u_ind % (lastTable.tab_meta.cntCols * 2)) + lastTable.tab_meta.cntCols*2
+							lastTable.buf_arr[u_ind].val = lastTable.buf_arr[r_ind].val
+							lastTable.buf_arr[u_ind].kind = 0
+							u_ind += 2
+						}
+					}
+					// --------------------------------------------------
+				}
+				lastTable.buf_arr[u_ind].val = &endStr
+				lastTable.buf_arr[u_ind].kind = 0
+				pad_end_size += len(endStr)
+				// ----------------------------------------------------------
+				lastTable.init_size = pad_beg_size + pad_end_size
+				lastTable.pad_row_size = pad_mid_size
+			}
+		}
+		// --------------------------------------------------------------------------
+		no_null_cnt := 0
+		for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+			for n := 0; n < lastTable.tab_meta.cntCols; n++ {
+				cell := a_dta_chunk.rows[j].cols[n]
+				if cell.Valid {
+					no_null_cnt += 1
+				}
+			}
+		}
+		var sqlParams []any = make([]any, no_null_cnt)
+		// ----------------------------------------------------------
+		arr_ind := 1
+		last_cell_pos := a_dta_chunk.usedlen * 2 * lastTable.tab_meta.cntCols
+		b_siz := lastTable.init_size + (a_dta_chunk.usedlen-1)*lastTable.pad_row_size
+
+		var param_pattern string
+
+		if dst_driver == "mysql" {
+			param_pattern = "?"
+		}
+		if dst_driver == "postgres" {
+			param_pattern = "$"
+		}
+		if dst_driver == "mssql" {
+			param_pattern = "@p"
+		}
+		// ----------------------------------------------------------
+		param_pos := 0
+		if dst_driver == "mysql" {
+			for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+				for n := 0; n < lastTable.tab_meta.cntCols; n++ {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind += 2
+						continue
+					}
+					lastTable.buf_arr[arr_ind].kind = 9
+					lastTable.buf_arr[arr_ind].val = &param_pattern
+					sqlParams[param_pos] = cell.String
+					param_pos += 1
+					b_siz += 1
+					arr_ind += 2
+					continue
+				}
+			}
+		} else {
+			for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+				for n := 0; n < lastTable.tab_meta.cntCols; n++ {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind += 2
+						continue
+					}
+					lastTable.buf_arr[arr_ind].kind = 9
+					str_val := param_pattern + strconv.Itoa(param_pos+1)
+					lastTable.buf_arr[arr_ind].val = &str_val
+					if lastTable.tab_meta.columnInfos[n].isKindBinary {
+						sqlParams[param_pos] = []byte(cell.String)
+					} else {
+						if dst_driver == "postgres" && strings.IndexByte(cell.String, 0) != -1 {
+							sqlParams[param_pos] = strings.ReplaceAll(cell.String, "\x00", "")
+						} else {
+							sqlParams[param_pos] = cell.String
+						}
+					}
+					param_pos += 1
+					b_siz += len(str_val)
+					arr_ind += 2
+					continue
+				}
+			}
+		}
+		// --------------------------------------------------------------------------
+		var b strings.Builder
+		b.Grow(b_siz)
+		for n := range lastTable.buf_arr[:last_cell_pos] {
+			a_cell := lastTable.buf_arr[n]
+			switch a_cell.kind {
+			case -1:
+				b.WriteString(nullStr)
+			case 0:
+				b.WriteString(*a_cell.val)
+			case 9:
+				b.WriteString(*a_cell.val)
+			}
+		}
+		b.WriteString(endStr)
+		a_str := b.String()
+		if mode_trace {
+			log.Printf("%s", a_str)
+		}
+		if mode_debug {
+			if len(a_str) != b_siz {
+				log.Printf("[%02d] dataChunkGenerator bad estimation real %d vs esti %d", id, len(a_str), b_siz)
+			}
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d ... sql len is %6d", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id, len(a_str))
+		}
+		select {
+		case <-ctx_root.Done():
+			return
+		case sql2inject <- insertchunk{table_id: lastTable.table_id, chunk_id: a_dta_chunk.chunk_id, rows_cnt: a_dta_chunk.usedlen, sql: &a_str, params: &sqlParams}:
+		}
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d ... ok sql2inject", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id)
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func dataChunkGeneratorSql(ctx_root context.Context, rowvalueschan chan datachunk, id int, tableInfos []metadataTable, sql2inject chan insertchunk, dst_driver string, cntBrowser int) {
+	mode_debug := isDebug(ctx_root)
+	mode_trace := isTrace(ctx_root)
+	// ----------------------------------------------------------------------------------
+	// we use a array of string to generate the final sql insert
+	//
+	//   row 1 |   insert into (   | col1  | , |  col2 | , | ....   | coln
+	//   row 2 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   row 3 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   row 4 |   ) , (           | col1  | , |  col2 | , | ....   | coln
+	//   end   |   ) ; \n
+	//
+	//    4 rows with n cols => 4*n*2 + 1
+	//
+	//  echo row need 1           prefix
+	//                cntCols     for value
+	//                cntCols - 1 for coma
+	//  and a final   suffix
+	//
+	//  total cells => insert_size x ( tab_meta.cntCols * 2 ) cells + 1
+	//
+	// ----------------------------------------------------------------------------------
+	nullStr := "NULL"
+	betwStr := "),("
+	endStr := ");\n"
+
+	var cntgenechunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabGenVars []*cachedataChunkGenerator
+	var lastTable *cachedataChunkGenerator
+
+	tabGenVars = make([]*cachedataChunkGenerator, cntBrowser+1)
+
+	// ----------------------------------------------------------------------------------
+	for {
+		var a_dta_chunk datachunk
+		select {
+		case <-ctx_root.Done():
+			a_dta_chunk.table_id = -1
+		case a_dta_chunk = <-rowvalueschan:
+		}
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d usedlen %5d", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id, a_dta_chunk.usedlen)
+		}
+		if a_dta_chunk.table_id == -1 {
+			break
+		}
+		cntgenechunk++
+
+		if lastTable != nil && lastTable.table_id == a_dta_chunk.table_id {
+			last_hit++
+		} else {
+			// ------------------------------------------------------------------
+			var tab_found int = -1
+			var empty_slot int = -1
+			var lru_slot int = -1
+			var lru_val int = math.MaxInt
+			for n := range tabGenVars {
+				if tabGenVars[n] == nil {
+					empty_slot = n
+				} else if tabGenVars[n].table_id == a_dta_chunk.table_id {
+					tab_found = n
+					break
+				} else {
+					if tabGenVars[n].lastusagecnt < lru_val {
+						lru_val = tabGenVars[n].lastusagecnt
+						lru_slot = n
+					}
+				}
+			}
+			if tab_found > -1 {
+				cache_hit++
+				lastTable = tabGenVars[tab_found]
+				lastTable.lastusagecnt = cntgenechunk
+				cache_miss++
+			} else {
+				if empty_slot == -1 {
+					empty_slot = lru_slot
+				}
+				var new_info cachedataChunkGenerator
+				tabGenVars[empty_slot] = &new_info
+				lastTable = &new_info
+				// ----------------------------------------------------------
+				lastTable.table_id = a_dta_chunk.table_id
+				// ----------------------------------------------------------
+				lastTable.tab_meta = &tableInfos[lastTable.table_id]
+				// ----------------------------------------------------------
+				lastTable.buf_arr = make([]colchunk, lastTable.tab_meta.insert_size*2*lastTable.tab_meta.cntCols+1)
+				// ----------------------------------------------------------
+				u_ind := 0
+				coma_str := ","
+				pad_beg_size := 0
+				pad_mid_size := 0 //  will be repeat =>  cnt rows -1
+				pad_end_size := 0
+				// ----------------------------------------------------------
+				// row 1
+				lastTable.buf_arr[u_ind].val = &lastTable.tab_meta.query_for_insert_head
+				lastTable.buf_arr[u_ind].kind = 0
+				pad_beg_size += len(lastTable.tab_meta.query_for_insert_head)
+				u_ind += 2
+				for c := 1; c < lastTable.tab_meta.cntCols; c++ {
+					lastTable.buf_arr[u_ind].val = &coma_str
+					lastTable.buf_arr[u_ind].kind = 0
+					pad_beg_size += 1
+					u_ind += 2
+				}
+				if lastTable.tab_meta.insert_size > 1 {
+					// --------------------------------------------------
+					// row 2
+					lastTable.buf_arr[u_ind].val = &betwStr
+					lastTable.buf_arr[u_ind].kind = 0
+					pad_mid_size += len(betwStr)
+					u_ind += 2
+					for c := 1; c < lastTable.tab_meta.cntCols; c++ {
+						lastTable.buf_arr[u_ind].val = &coma_str
+						lastTable.buf_arr[u_ind].kind = 0
+						pad_mid_size += 1
+						u_ind += 2
+					}
+					// --------------------------------------------------
+					for r := 2; r < lastTable.tab_meta.insert_size; r++ {
+						for c := 0; c < lastTable.tab_meta.cntCols; c++ {
+							r_ind := (u_ind % (lastTable.tab_meta.cntCols * 2)) + lastTable.tab_meta.cntCols*2
+							lastTable.buf_arr[u_ind].val = lastTable.buf_arr[r_ind].val
+							lastTable.buf_arr[u_ind].kind = 0
+							u_ind += 2
+						}
+					}
+					// --------------------------------------------------
+				}
+				lastTable.buf_arr[u_ind].val = &endStr
+				lastTable.buf_arr[u_ind].kind = 0
+				pad_end_size += len(endStr)
+				// ----------------------------------------------------------
+				lastTable.init_size = pad_beg_size + pad_end_size
+				lastTable.pad_row_size = pad_mid_size
+			}
+		}
+
+		last_cell_pos := a_dta_chunk.usedlen * 2 * lastTable.tab_meta.cntCols
+		b_siz := lastTable.init_size + (a_dta_chunk.usedlen-1)*lastTable.pad_row_size
+
+		for n := 0; n < lastTable.tab_meta.cntCols; n++ {
+			arr_ind := 1 + n*2 + (a_dta_chunk.usedlen-1)*2*lastTable.tab_meta.cntCols
+			arr_ind_inc := 2 * lastTable.tab_meta.cntCols
+			// ------------------------------------------------------------------
+			if lastTable.tab_meta.columnInfos[n].isKindBinary && dst_driver == "mysql" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					s_ptr, cell_size := quoteBinary(&cell.String)
+					lastTable.buf_arr[arr_ind].kind = 2
+					lastTable.buf_arr[arr_ind].val = s_ptr
+					// 7 for _binary and 1 for space and 2 for quotes
+					b_siz += cell_size + 7 + 1 + 2
+					arr_ind -= arr_ind_inc
+				}
+			} else if lastTable.tab_meta.columnInfos[n].isKindBinary && dst_driver == "postgres" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					s_ptr := hex.EncodeToString([]byte(cell.String))
+					cell_size := len(s_ptr)
+					lastTable.buf_arr[arr_ind].kind = 2
+					lastTable.buf_arr[arr_ind].val = &s_ptr
+					// 7 for decode( and 2 for quotes and 7 for ,'hex')
+					b_siz += cell_size + 7 + 2 + 7
+					arr_ind -= arr_ind_inc
+				}
+			} else if lastTable.tab_meta.columnInfos[n].isKindBinary && dst_driver == "mssql" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					s_ptr := hex.EncodeToString([]byte(cell.String))
+					cell_size := len(s_ptr)
+					lastTable.buf_arr[arr_ind].kind = 2
+					lastTable.buf_arr[arr_ind].val = &s_ptr
+					// convert(varbinary(max),'HXHXHXHHXHXHX',2)
+					//      23 + 2 for for quote  + 3 for ,2)
+					b_siz += cell_size + 23 + 2 + 2 + 1
+					arr_ind -= arr_ind_inc
+				}
+			} else if lastTable.tab_meta.columnInfos[n].mustBeQuote && dst_driver == "mysql" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					s_ptr, cell_size, need_quote, new_char := needCopyForquoteStringMysql(&cell.String)
+					if need_quote != -1 {
+						s_ptr, cell_size = quoteStringFromPosMysql(&cell.String, cell_size, need_quote, new_char)
+					}
+					b_siz += cell_size + 2
+					lastTable.buf_arr[arr_ind].kind = 1
+					lastTable.buf_arr[arr_ind].val = s_ptr
+					arr_ind -= arr_ind_inc
+				}
+			} else if lastTable.tab_meta.columnInfos[n].mustBeQuote && dst_driver == "postgres" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					s_ptr, cell_size, need_quote, new_char := needCopyForquoteStringPostgres(&cell.String)
+					if need_quote != -1 {
+						s_ptr, cell_size = quoteStringFromPosPostgres(&cell.String, cell_size, need_quote, new_char)
+						lastTable.buf_arr[arr_ind].kind = 3
+						b_siz += cell_size + 3
+					} else {
+						lastTable.buf_arr[arr_ind].kind = 1
+						b_siz += cell_size + 2
+					}
+					lastTable.buf_arr[arr_ind].val = s_ptr
+					arr_ind -= arr_ind_inc
+				}
+			} else if lastTable.tab_meta.columnInfos[n].mustBeQuote && dst_driver == "mssql" {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					if !stringIsASCII(&cell.String) {
+						s_ptr, cell_size, need_quote, new_char := needCopyForquoteStringMsSql(&cell.String)
+						if need_quote != -1 {
+							s_ptr, cell_size = quoteStringFromPosMsSql(&cell.String, cell_size, need_quote, new_char)
+						}
+						lastTable.buf_arr[arr_ind].kind = 1
+						b_siz += cell_size + 2
+						lastTable.buf_arr[arr_ind].val = s_ptr
+						arr_ind -= arr_ind_inc
+					} else {
+						lastTable.buf_arr[arr_ind].kind = 1
+						lastTable.buf_arr[arr_ind].val = &cell.String
+						arr_ind -= arr_ind_inc
+					}
+				}
+			} else if lastTable.tab_meta.columnInfos[n].isKindFloat {
+				var f_prec uint = 24
+				if lastTable.tab_meta.columnInfos[n].colType == "double" {
+					f_prec = 53
+				}
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					f := new(big.Float).SetPrec(f_prec)
+					f.SetString(cell.String)
+					v := f.Text('f', -1)
+					b_siz += len(v)
+					lastTable.buf_arr[arr_ind].kind = 0
+					lastTable.buf_arr[arr_ind].val = &v
+					arr_ind -= arr_ind_inc
+				}
+			} else {
+				for j := a_dta_chunk.usedlen - 1; j >= 0; j-- {
+					cell := a_dta_chunk.rows[j].cols[n]
+					if !cell.Valid {
+						lastTable.buf_arr[arr_ind].kind = -1
+						b_siz += 4
+						arr_ind -= arr_ind_inc
+						continue
+					}
+					lastTable.buf_arr[arr_ind].kind = 0
+					lastTable.buf_arr[arr_ind].val = &cell.String
+					b_siz += len(cell.String)
+					arr_ind -= arr_ind_inc
+				}
+			}
+			// ------------------------------------------------------------------
+		}
+		// --------------------------------------------------------------------------
+		var b strings.Builder
+		b.Grow(b_siz)
+		for n := range lastTable.buf_arr[:last_cell_pos] {
+			a_cell := lastTable.buf_arr[n]
+			switch a_cell.kind {
+			case -1:
+				b.WriteString(nullStr)
+			case 0:
+				b.WriteString(*a_cell.val)
+			case 1:
+				b.WriteString("'")
+				b.WriteString(*a_cell.val)
+				b.WriteString("'")
+			case 2:
+				if dst_driver == "mysql" {
+					b.WriteString("_binary '")
+					b.WriteString(*a_cell.val)
+					b.WriteString("'")
+				} else if dst_driver == "mssql" {
+					b.WriteString("convert(varbinary(max),'")
+					b.WriteString(*a_cell.val)
+					b.WriteString("',2)")
+				} else {
+					b.WriteString("decode('")
+					b.WriteString(*a_cell.val)
+					b.WriteString("','hex')")
+				}
+			case 3:
+				b.WriteString("E'")
+				b.WriteString(*a_cell.val)
+				b.WriteString("'")
+			}
+		}
+		b.WriteString(endStr)
+		a_str := b.String()
+		if mode_trace {
+			log.Printf("%s", a_str)
+		}
+		if mode_debug {
+			if len(a_str) != b_siz {
+				log.Printf("[%02d] dataChunkGenerator bad estimation real %d vs esti %d", id, len(a_str), b_siz)
+			}
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d ... sql len is %6d", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id, len(a_str))
+		}
+		select {
+		case <-ctx_root.Done():
+			return
+		case sql2inject <- insertchunk{table_id: lastTable.table_id, chunk_id: a_dta_chunk.chunk_id, rows_cnt: a_dta_chunk.usedlen, sql: &a_str}:
+		}
+		if mode_debug {
+			log.Printf("[%02d] dataChunkGenerator table %03d chunk %12d ... ok sql2inject", id, a_dta_chunk.table_id, a_dta_chunk.chunk_id)
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func dataChunkGeneratorCsv(ctx_root context.Context, rowvalueschan chan datachunk, id int, tableInfos []metadataTable, sql2inject chan insertchunk, dst_driver string, cntBrowser int) {
+	// ----------------------------------------------------------------------------------
+	var buf_arr []*string
+	var last_table_id int = -1
+	var tab_meta *metadataTable
+	// ----------------------------------------------------------------------------------
+	var a_dta_chunk datachunk
+	select {
+	case <-ctx_root.Done():
+		a_dta_chunk.table_id = -1
+	case a_dta_chunk = <-rowvalueschan:
+	}
+	for {
+		if a_dta_chunk.table_id == -1 {
+			break
+		}
+		if last_table_id != a_dta_chunk.table_id {
+			last_table_id = a_dta_chunk.table_id
+			tab_meta = &tableInfos[last_table_id]
+			// ------------------------------------------------------------------
+			// we use a array of string to generate the final csv
+			//
+			//   row 1 | col1  | , |  col2 | , | ....   | coln | <return line>
+			//   row 2 | col1  | , |  col2 | , | ....   | coln | <return line>
+			//   row 3 | col1  | , |  col2 | , | ....   | coln | <return line>
+			//   row 4 | col1  | , |  col2 | , | ....   | coln | <return line>
+			//
+			//    4 rows with n cols => 4*cols*2
+			//
+			//  echo row need cntCols     for value
+			//                cntCols - 1 for sep
+			//                1           for line break
+			//
+			//  total cells => insert_size x ( tab_meta.cntCols * 2 ) cells
+			//
+			buf_arr = make([]*string, tab_meta.insert_size*2*tab_meta.cntCols)
+			coma_str := ","
+			eol_str := "\n"
+			b_ind := 1
+			for r := 0; r < tab_meta.insert_size; r++ {
+				for c := 1; c < tab_meta.cntCols; c++ {
+					buf_arr[b_ind] = &coma_str
+					b_ind += 2
+				}
+				buf_arr[b_ind] = &eol_str
+				b_ind += 2
+			}
+		}
+		// --------------------------------------------------------------------------
+		nullStr := "\\N"
+		emptStr := ""
+		b_siz := a_dta_chunk.usedlen * tab_meta.cntCols
+		// --------------------------------------------------------------------------
+		for n := 0; n < tab_meta.cntCols; n++ {
+			b_ind := n * 2
+			b_ind_inc := 2 * tab_meta.cntCols
+			// ------------------------------------------------------------------
+			if tab_meta.columnInfos[n].haveFract {
+				for j := 0; j < a_dta_chunk.usedlen; j++ {
+					if !a_dta_chunk.rows[j].cols[n].Valid {
+						buf_arr[b_ind] = &emptStr
+					} else {
+						timeSec, timeFract, dotFound := strings.Cut(a_dta_chunk.rows[j].cols[n].String, ".")
+						if dotFound {
+							timeFract = strings.TrimRight(timeFract, "0")
+							if len(timeFract) == 1 {
+								timeFract = timeFract + "0"
+							}
+							a_str := timeSec + "." + timeFract
+							buf_arr[b_ind] = &a_str
+						} else {
+							buf_arr[b_ind] = &a_dta_chunk.rows[j].cols[n].String
+						}
+						b_siz += len(*buf_arr[b_ind])
+					}
+					b_ind += b_ind_inc
+				}
+			} else if tab_meta.columnInfos[n].mustBeQuote && (tab_meta.columnInfos[n].isKindChar || tab_meta.columnInfos[n].isKindBinary) {
+				for j := 0; j < a_dta_chunk.usedlen; j++ {
+					if !a_dta_chunk.rows[j].cols[n].Valid {
+						buf_arr[b_ind] = &nullStr
+						b_siz += 2
+					} else {
+						if needQuoteForCsv(&a_dta_chunk.rows[j].cols[n].String) {
+							a_str := "\"" + strings.ReplaceAll(a_dta_chunk.rows[j].cols[n].String, "\"", "\"\"") + "\""
+							buf_arr[b_ind] = &a_str
+						} else {
+							buf_arr[b_ind] = &a_dta_chunk.rows[j].cols[n].String
+						}
+						b_siz += len(*buf_arr[b_ind])
+					}
+					b_ind += b_ind_inc
+				}
+			} else {
+				for j := 0; j < a_dta_chunk.usedlen; j++ {
+					if !a_dta_chunk.rows[j].cols[n].Valid {
+						buf_arr[b_ind] = &emptStr
+					} else {
+						buf_arr[b_ind] = &a_dta_chunk.rows[j].cols[n].String
+						b_siz += len(*buf_arr[b_ind])
+					}
+					b_ind += b_ind_inc
+				}
+			}
+			// -----------------------------------------------------------------
+		}
+		var b strings.Builder
+		b.Grow(b_siz)
+		for n := range buf_arr[:a_dta_chunk.usedlen*2*tab_meta.cntCols] {
+			b.WriteString(*buf_arr[n])
+		}
+		j_str := b.String()
+		select {
+		case <-ctx_root.Done():
+			return
+		case sql2inject <- insertchunk{table_id: last_table_id, rows_cnt: a_dta_chunk.usedlen, sql: &j_str}:
+		}
+		// --------------------------------------------------------------------------
+		select {
+		case <-ctx_root.Done():
+			a_dta_chunk.table_id = -1
+		case a_dta_chunk = <-rowvalueschan:
+		}
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func dataChunkGeneratorNul(ctx_root context.Context, rowvalueschan chan datachunk, id int, tableInfos []metadataTable, sql2inject chan insertchunk, dst_driver string, cntBrowser int) {
+	// ----------------------------------------------------------------------------------
+	var a_dta_chunk datachunk
+	select {
+	case <-ctx_root.Done():
+		a_dta_chunk.table_id = -1
+	case a_dta_chunk = <-rowvalueschan:
+	}
+	for {
+		if a_dta_chunk.table_id == -1 {
+			break
+		}
+		// --------------------------------------------------------------------------
+		select {
+		case <-ctx_root.Done():
+			a_dta_chunk.table_id = -1
+		case a_dta_chunk = <-rowvalueschan:
+		}
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+type cachetableFileWriter struct {
+	table_id     int
+	lastusagecnt int
+	und_fh       *os.File
+	zst_enc      *zstd.Encoder
+}
+
+// ------------------------------------------------------------------------------------------
+func tableFileWriter(ctx_root context.Context, sql2inject chan insertchunk, id int, tableInfos []metadataTable, dumpdir string, dumpfiletemplate string, dumpmode string, dumpheader bool, dumpcompress string, z_level int, z_para int, cntBrowser int, stats2push chan writerStat, progress ProgressFunc) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		if dumpcompress == "zstd" {
+			log.Printf("tableFileWriter[%d] start mode %s %s lvl %d conc %d\n", id, dumpmode, dumpcompress, z_level, z_para)
+		} else {
+			log.Printf("tableFileWriter[%d] start mode %s \n", id, dumpmode)
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	file_is_empty := make([]bool, 0)
+	file_name := make([]string, 0)
+	for _, v := range tableInfos {
+		var fname string
+		fname = strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(dumpfiletemplate, "%d", v.dbName), "%t", v.tbName), "%p", strconv.Itoa(id)), "%m", "."+dumpmode)
+		if dumpcompress == "zstd" {
+			fname = strings.ReplaceAll(fname, "%z", ".zst")
+		} else {
+			fname = strings.ReplaceAll(fname, "%z", "")
+		}
+		fname = dumpdir + strings.ReplaceAll(fname, "%%", "%")
+		if dumpmode != "nul" {
+			fh, err := os.OpenFile(fname, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				failf("can not openfile %s\n%w", fname, err)
+			} else {
+				fh.Close()
+			}
+		}
+		file_is_empty = append(file_is_empty, true)
+		file_name = append(file_name, fname)
+	}
+	file_rows := make([]int64, len(tableInfos))
+	// ----------------------------------------------------------------------------------
+	var cntwritechunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabWrtVars []*cachetableFileWriter
+	var lastTable *cachetableFileWriter
+
+	tabWrtVars = make([]*cachetableFileWriter, cntBrowser+1)
+	// ----------------------------------------------------------------------------------
+	if dumpcompress == "zstd" {
+		// --------------------------------------------------------------------------
+		for {
+			var a_insert_sql insertchunk
+			select {
+			case <-ctx_root.Done():
+				a_insert_sql.sql = nil
+			case a_insert_sql = <-sql2inject:
+			}
+			if a_insert_sql.sql == nil {
+				break
+			}
+			cntwritechunk++
+			// ------------------------------------------------------------------
+			if lastTable != nil && lastTable.table_id == a_insert_sql.table_id {
+				last_hit++
+			} else {
+				// ----------------------------------------------------------
+				var tab_found int = -1
+				var empty_slot int = -1
+				var lru_slot int = -1
+				var lru_val int = math.MaxInt
+				for n := range tabWrtVars {
+					if tabWrtVars[n] == nil {
+						empty_slot = n
+					} else if tabWrtVars[n].table_id == a_insert_sql.table_id {
+						tab_found = n
+						break
+					} else {
+						if tabWrtVars[n].lastusagecnt < lru_val {
+							lru_val = tabWrtVars[n].lastusagecnt
+							lru_slot = n
+						}
+					}
+				}
+				if tab_found > -1 {
+					cache_hit++
+					lastTable = tabWrtVars[tab_found]
+					lastTable.lastusagecnt = cntwritechunk
+					cache_miss++
+				} else {
+					if empty_slot == -1 {
+						empty_slot = lru_slot
+						// ------------------------------------------
+						if tabWrtVars[lru_slot].und_fh != nil {
+							if tabWrtVars[lru_slot].zst_enc != nil {
+								tabWrtVars[lru_slot].zst_enc.Close()
+							}
+							tabWrtVars[lru_slot].und_fh.Close()
+						}
+						// ------------------------------------------
+					}
+					var new_info cachetableFileWriter
+					tabWrtVars[empty_slot] = &new_info
+					lastTable = &new_info
+					// -----------------------------------------
+					lastTable.table_id = a_insert_sql.table_id
+					// --------------------------------------------------
+					fname := file_name[a_insert_sql.table_id]
+					var err error
+					lastTable.und_fh, err = os.OpenFile(fname, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+					if err != nil {
+						failf("can not openfile %s\n%w", fname, err)
+					}
+					lastTable.zst_enc, err = zstd.NewWriter(lastTable.und_fh, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(z_level)), zstd.WithEncoderConcurrency(z_para))
+					if err != nil {
+						failf("can not create zstd.NewWriter\n%w", err)
+					}
+					if file_is_empty[a_insert_sql.table_id] {
+						if dumpheader {
+							if dumpmode == "csv" {
+								chunkReaderDumpHeader(dumpmode, lastTable.zst_enc, tableInfos[a_insert_sql.table_id].listColsCSV)
+							} else {
+								chunkReaderDumpHeader(dumpmode, lastTable.zst_enc, tableInfos[a_insert_sql.table_id].listColsSQL)
+							}
+						}
+						file_is_empty[a_insert_sql.table_id] = false
+					}
+				}
+			}
+			// ------------------------------------------------------------------
+			if mode_debug {
+				log.Printf("[%02d] tableFileWriter table %03d chunk %12d sql len %6d", id, a_insert_sql.table_id, a_insert_sql.chunk_id, len(*a_insert_sql.sql))
+			}
+			// ------------------------------------------------------------------
+			io.WriteString(lastTable.zst_enc, *a_insert_sql.sql)
+			file_rows[a_insert_sql.table_id] += int64(a_insert_sql.rows_cnt)
+			progress.emit(ProgressEvent{Stage: Progress_RowsWritten, Table: tableInfos[a_insert_sql.table_id].fullName, Rows: int64(a_insert_sql.rows_cnt)})
+			tableInfos[a_insert_sql.table_id].progress.written(a_insert_sql.rows_cnt, a_insert_sql.size())
+			// ------------------------------------------------------------------
+		}
+		// --------------------------------------------------------------------------
+		for n := range tabWrtVars {
+			if tabWrtVars[n] != nil {
+				if tabWrtVars[n].zst_enc != nil {
+					tabWrtVars[n].zst_enc.Close()
+				}
+				tabWrtVars[n].und_fh.Close()
+			}
+		}
+	} else {
+		for {
+			var a_insert_sql insertchunk
+			select {
+			case <-ctx_root.Done():
+				a_insert_sql.sql = nil
+			case a_insert_sql = <-sql2inject:
+			}
+			if a_insert_sql.sql == nil {
+				break
+			}
+			cntwritechunk++
+			// ------------------------------------------------------------------
+			if lastTable != nil && lastTable.table_id == a_insert_sql.table_id {
+				last_hit++
+			} else {
+				// ----------------------------------------------------------
+				var tab_found int = -1
+				var empty_slot int = -1
+				var lru_slot int = -1
+				var lru_val int = math.MaxInt
+				for n := range tabWrtVars {
+					if tabWrtVars[n] == nil {
+						empty_slot = n
+					} else if tabWrtVars[n].table_id == a_insert_sql.table_id {
+						tab_found = n
+						break
+					} else {
+						if tabWrtVars[n].lastusagecnt < lru_val {
+							lru_val = tabWrtVars[n].lastusagecnt
+							lru_slot = n
+						}
+					}
+				}
+				if tab_found > -1 {
+					cache_hit++
+					lastTable = tabWrtVars[tab_found]
+					lastTable.lastusagecnt = cntwritechunk
+					cache_miss++
+				} else {
+					if empty_slot == -1 {
+						empty_slot = lru_slot
+						// ------------------------------------------
+						if tabWrtVars[lru_slot].und_fh != nil {
+							tabWrtVars[lru_slot].und_fh.Close()
+						}
+						// ------------------------------------------
+					}
+					var new_info cachetableFileWriter
+					tabWrtVars[empty_slot] = &new_info
+					lastTable = &new_info
+					// -----------------------------------------
+					lastTable.table_id = a_insert_sql.table_id
+					// --------------------------------------------------
+					fname := file_name[lastTable.table_id]
+					var err error
+					lastTable.und_fh, err = os.OpenFile(fname, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+					if err != nil {
+						failf("can not openfile %s\n%w", fname, err)
+					}
+					if file_is_empty[lastTable.table_id] {
+						if dumpheader {
+							if dumpmode == "csv" {
+								chunkReaderDumpHeader(dumpmode, lastTable.und_fh, tableInfos[lastTable.table_id].listColsCSV)
+							} else {
+								chunkReaderDumpHeader(dumpmode, lastTable.und_fh, tableInfos[lastTable.table_id].listColsSQL)
+							}
+						}
+						file_is_empty[lastTable.table_id] = false
+					}
+				}
+			}
+			// ------------------------------------------------------------------
+			io.WriteString(lastTable.und_fh, *a_insert_sql.sql)
+			file_rows[a_insert_sql.table_id] += int64(a_insert_sql.rows_cnt)
+			progress.emit(ProgressEvent{Stage: Progress_RowsWritten, Table: tableInfos[a_insert_sql.table_id].fullName, Rows: int64(a_insert_sql.rows_cnt)})
+			tableInfos[a_insert_sql.table_id].progress.written(a_insert_sql.rows_cnt, a_insert_sql.size())
+			// ------------------------------------------------------------------
+			if mode_debug {
+				log.Printf("[%02d] tableFileWriter table %03d chunk %12d wait next", id, a_insert_sql.table_id, a_insert_sql.chunk_id)
+			}
+			// ------------------------------------------------------------------
+		}
+		// --------------------------------------------------------------------------
+		for n := range tabWrtVars {
+			if tabWrtVars[n] != nil {
+				tabWrtVars[n].und_fh.Close()
+			}
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	// files are closed , zstd frames are complete , but the dump is not
+	if ctx_root.Err() != nil {
+		for n := range file_name {
+			r_err := os.Remove(file_name[n])
+			if r_err != nil && !os.IsNotExist(r_err) {
+				log.Printf("tableFileWriter[%d] can not remove %s\n%s", id, file_name[n], r_err.Error())
+			}
+		}
+		log.Printf("tableFileWriter[%d] interrupted , %d files removed", id, len(file_name))
+		return
+	}
+	stats2push <- writerStat{id: id, fileNames: file_name, rows: file_rows}
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Printf("tableFileWriter[%d] finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// what a writer did , sent once when it finish . slices are indexed by table id
+type writerStat struct {
+	id        int
+	fileNames []string
+	rows      []int64
+}
+
+// ------------------------------------------------------------------------------------------
+type manifestFile struct {
+	Name  string `json:"name"`
+	Rows  int64  `json:"rows"`
+	Bytes int64  `json:"bytes"`
+}
+
+type manifestTable struct {
+	Schema string         `json:"schema"`
+	Table  string         `json:"table"`
+	Where  string         `json:"where,omitempty"`
+	Rows   int64          `json:"rows"`
+	Bytes  int64          `json:"bytes"`
+	Files  []manifestFile `json:"files"`
+}
+
+// for postgres binlog_file is the exported snapshot and binlog_pos the LSN , for mssql both are the LSN
+type dumpManifest struct {
+	Driver        string          `json:"driver"`
+	ServerUuid    string          `json:"server_uuid"`
+	ServerVersion string          `json:"server_version"`
+	FromReplica   bool            `json:"from_replica"`
+	BinlogFile    string          `json:"binlog_file"`
+	BinlogPos     int             `json:"binlog_pos"`
+	GtidSet       string          `json:"gtid_set"`
+	StartTime     string          `json:"start_time"`
+	EndTime       string          `json:"end_time"`
+	DumpMode      string          `json:"dump_mode"`
+	DumpCompress  string          `json:"dump_compress"`
+	DdlFiles      []string        `json:"ddl_files,omitempty"`
+	Tables        []manifestTable `json:"tables"`
+}
+
+// ------------------------------------------------------------------------------------------
+func getServerInfo(run_ctx context.Context, adbConn *sql.Conn, srcdriver string) (string, string) {
+	var v_uuid string
+	var v_version string
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_err := adbConn.QueryRowContext(ctx, getSqlDialect(srcdriver).ServerInfoQuery()).Scan(&v_uuid, &v_version)
+	if q_err != nil {
+		log.Printf("can not get server informations\n%s", phaseError(ctx, q_err, "").Error())
+	}
+	return v_uuid, v_version
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// manifest.json describe the dump , so a replica or a cdc pipeline can be started from the
+// position of the snapshot without parsing the logs
+func writeDumpManifest(dumpdir string, manifest dumpManifest, tableInfos []metadataTable, writerStats []writerStat) error {
+	sort.Slice(writerStats, func(i, j int) bool { return writerStats[i].id < writerStats[j].id })
+	for t, v := range tableInfos {
+		a_table := manifestTable{Schema: v.dbName, Table: v.tbName, Where: v.where, Files: make([]manifestFile, 0)}
+		files_pos := make(map[string]int)
+		for _, w := range writerStats {
+			fname := w.fileNames[t]
+			n, found := files_pos[fname]
+			if !found {
+				n = len(a_table.Files)
+				files_pos[fname] = n
+				a_table.Files = append(a_table.Files, manifestFile{Name: strings.TrimPrefix(fname, dumpdir)})
+				fi, s_err := os.Stat(fname)
+				if s_err != nil {
+					log.Printf("can not stat file %s\n%s", fname, s_err.Error())
+				} else {
+					a_table.Files[n].Bytes = fi.Size()
+					a_table.Bytes += fi.Size()
+				}
+			}
+			a_table.Files[n].Rows += w.rows[t]
+			a_table.Rows += w.rows[t]
+		}
+		manifest.Tables = append(manifest.Tables, a_table)
+	}
+	// ----------------------------------------------------------------------------------
+	j_data, j_err := json.MarshalIndent(manifest, "", "  ")
+	if j_err != nil {
+		return fmt.Errorf("can not generate manifest\n%w", j_err)
+	}
+	fname := dumpdir + "manifest.json"
+	w_err := os.WriteFile(fname, append(j_data, '\n'), 0o644)
+	if w_err != nil {
+		return fmt.Errorf("can not write manifest %s\n%w", fname, w_err)
+	}
+	log.Printf("manifest written in %s", fname)
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+func tableCopyWriter(ctx_root context.Context, sql2inject chan insertchunk, adbConn *sql.Conn, id int, tableInfos []metadataTable, progress ProgressFunc) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableCopyWriter[%d] start\n", id)
+	}
+	var a_insert_sql insertchunk
+	select {
+	case <-ctx_root.Done():
+		a_insert_sql.sql = nil
+	case a_insert_sql = <-sql2inject:
+	}
+	for a_insert_sql.sql != nil {
+		// --------------------------------------------------------------------------
+		table_name := tableInfos[a_insert_sql.table_id].fullName
+		ctx, cancel := phaseContext(ctx_root, timeout_write, 0)
+		if a_insert_sql.params != nil {
+			_, e_err := adbConn.ExecContext(ctx, *a_insert_sql.sql, *a_insert_sql.params...)
+			cancel()
+			if e_err != nil {
+				if ctx_root.Err() != nil {
+					break
+				}
+				log.Printf("error with :\n%s", *a_insert_sql.sql)
+				log.Printf("with params array of %d elems", len(*a_insert_sql.params))
+				failf("thread %d , can not insert a chunk\n%w", id, phaseError(ctx, e_err, table_name))
+			}
+		} else {
+			_, e_err := adbConn.ExecContext(ctx, *a_insert_sql.sql)
+			cancel()
+			if e_err != nil {
+				if ctx_root.Err() != nil {
+					break
+				}
+				log.Printf("error with :\n%s", *a_insert_sql.sql)
+				failf("thread %d , can not insert a chunk\n%w", id, phaseError(ctx, e_err, table_name))
+			}
+		}
+		progress.emit(ProgressEvent{Stage: Progress_RowsWritten, Table: tableInfos[a_insert_sql.table_id].fullName, Rows: int64(a_insert_sql.rows_cnt)})
+		tableInfos[a_insert_sql.table_id].progress.written(a_insert_sql.rows_cnt, a_insert_sql.size())
+		tableInfos[a_insert_sql.table_id].deferred.done()
+		// --------------------------------------------------------------------------
+		select {
+		case <-ctx_root.Done():
+			a_insert_sql.sql = nil
+		case a_insert_sql = <-sql2inject:
+		}
+	}
+	if mode_debug {
+		log.Printf("tableCopyWriter[%d] finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// DumpOptions are the settings of a Dumper , they match the flags of paradump
+//
+// Destination and DstWriters are only used with DumpMode cpy , DstSchemas are the schemas
+// on the destination ( Schemas when empty ) . Tables and ExcludeTables are table patterns
+// ( table , schema.table , shell globs or /regex/ ) , Tables empty means all tables of Schemas .
+// Where are table filters ( pattern:expression ) , only the rows matching the expression are
+// dumped . Columns and ExcludeColumns are column lists ( pattern:col1,col2 ) , only the
+// matching columns and the columns of the primary key are dumped
+//
+// with AllSchemas , Schemas are every schema of the source except the system schemas and the
+// ones matching ExcludeSchemas ( shell globs or /regex/ ) , files go into a subdirectory per
+// schema and DstSchemas are the same schemas
+//
+// with a mysql source and DumpMode sql or csv the ddl of each schema is written with the
+// data , DataOnly skips it and SchemaOnly writes only the ddl
+//
+// with DumpMode cpy and a mysql source , CreateTables creates the missing tables on the
+// destination from the columns and the primary key of the source ( see mapMysqlColumnType ) .
+// with DeferIndexes the secondary indexes of the destination are saved in this file and
+// dropped before the copy , IndexBuilders create them again as soon as a table is copied
+//
+// with ArithmeticChunks a table with a primary key of one integer column is cut in
+// intervals of its pk from min to max , without browsing it . with SplitTables a table of
+// more than 10 chunks per browser is cut in ranges of its pk , one per browser , when the
+// first column of its pk is an integer , the log says when a table is not cut
+//
+// with ChunkTargetTime or ChunkTargetBytes the browsers start each table with ChunkSize
+// rows per chunk , then cut chunks that the readers read in ChunkTargetTime or that hold
+// ChunkTargetBytes of values , from the average of the last chunks of the table and within
+// ChunkMin and ChunkMax rows ( see chunkSizer )
+//
+// with ProgressInterval , Progress gets a status event at each interval while the tables
+// are read , and a last one when the pipeline is done ( see ProgressStatus )
+type DumpOptions struct {
+	Source           DbServer
+	Destination      DbServer
+	SnapshotLock     string
+	FromReplica      bool
+	Schemas          []string
+	DstSchemas       []string
+	AllSchemas       bool
+	ExcludeSchemas   []string
+	Tables           []string
+	ExcludeTables    []string
+	Where            []string
+	Columns          []string
+	ExcludeColumns   []string
+	GuessPrimaryKey  bool
+	Browsers         int
+	Readers          int
+	ChunkSize        int
+	ChunkTargetTime  time.Duration
+	ChunkTargetBytes int64
+	ChunkMin         int
+	ChunkMax         int
+	ArithmeticChunks bool
+	SplitTables      bool
+	InsertSize       int
+	DumpMode         string
+	DumpFile         string
+	DumpDir          string
+	DumpHeader       bool
+	DumpInsert       string
+	DumpCompress     string
+	SchemaOnly       bool
+	DataOnly         bool
+	CreateTables     bool
+	DeferIndexes     string
+	IndexBuilders    int
+	CompressLevel    int
+	CompressConcur   int
+	FileWriters      int
+	DstWriters       int
+	LoopCount        int
+	Debug            bool
+	Trace            bool
+	Timeouts         Timeouts
+	Progress         ProgressFunc
+	ProgressInterval time.Duration
+}
+
+// ------------------------------------------------------------------------------------------
+type Dumper struct {
+	opts DumpOptions
+}
+
+func NewDumper(opts DumpOptions) *Dumper {
+	return &Dumper{opts: opts}
+}
+
+// ------------------------------------------------------------------------------------------
+// Run returns the first failure , or ctx.Err() when ctx is cancelled . the manifest is only
+// written when every table is dumped , files of an unfinished dump are removed
+func (d *Dumper) Run(ctx context.Context) (ret_err error) {
+	o := d.opts
+	// the settings of the run go down with its contexts , the setup stops with ctx ,
+	// release_ctx is not cancelled with ctx , for the sessions of the snapshot that must be
+	// given back
+	ctx = withRunSettings(ctx, o.Debug, o.Trace, o.Timeouts)
+	release_ctx := detachedContext(ctx)
+	mode_debug := isDebug(ctx)
+	mode_trace := isTrace(ctx)
+	if o.DumpMode != "sql" && o.DumpMode != "csv" && o.DumpMode != "nul" && o.DumpMode != "cpy" {
+		return fmt.Errorf("invalid dump mode %s", o.DumpMode)
+	}
+	if len(o.Schemas) == 0 && !o.AllSchemas {
+		return fmt.Errorf("no schema specified")
+	}
+	if o.AllSchemas && (len(o.Schemas) != 0 || len(o.DstSchemas) != 0) {
+		return fmt.Errorf("can not use all schemas with a list of schemas")
+	}
+	if _, p_err := parseSchemaPatterns(o.ExcludeSchemas); p_err != nil {
+		return p_err
+	}
+	for _, patterns := range [][]string{o.Tables, o.ExcludeTables} {
+		if _, p_err := parseTablePatterns(patterns); p_err != nil {
+			return p_err
+		}
+	}
+	if _, w_err := parseTableWheres(o.Where); w_err != nil {
+		return w_err
+	}
+	for _, lists := range [][]string{o.Columns, o.ExcludeColumns} {
+		if _, c_err := parseTableColumns(lists); c_err != nil {
+			return c_err
+		}
+	}
+	if o.SchemaOnly && o.DataOnly {
+		return fmt.Errorf("can not use schema only with data only")
+	}
+	dump_ddl := o.Source.Driver == "mysql" && (o.DumpMode == "sql" || o.DumpMode == "csv") && !o.DataOnly
+	if o.SchemaOnly && !dump_ddl {
+		return fmt.Errorf("the ddl is only dumped from mysql with dump mode sql or csv")
+	}
+	if o.CreateTables && (o.DumpMode != "cpy" || o.Source.Driver != "mysql") {
+		return fmt.Errorf("tables can only be created from mysql with dump mode cpy")
+	}
+	if len(o.DeferIndexes) != 0 && o.DumpMode != "cpy" {
+		return fmt.Errorf("indexes can only be deferred with dump mode cpy")
+	}
+	if len(o.DeferIndexes) != 0 && o.IndexBuilders < 1 {
+		o.IndexBuilders = 1
+	}
+	if o.ChunkTargetTime < 0 || o.ChunkTargetBytes < 0 {
+		return fmt.Errorf("the targets of the chunk size can not be negative")
+	}
+	adaptive_chunks := o.ChunkTargetTime > 0 || o.ChunkTargetBytes > 0
+	if adaptive_chunks && (o.ChunkMin < 1 || o.ChunkMin > o.ChunkMax) {
+		return fmt.Errorf("invalid bounds of the chunk size , %d must be at least 1 and less or equal then %d", o.ChunkMin, o.ChunkMax)
+	}
+	if o.LoopCount < 1 {
+		o.LoopCount = 1
+	}
+	if len(o.DstSchemas) == 0 {
+		o.DstSchemas = o.Schemas
+	}
+	// ----------------------------------------------------------------------------------
+	// with all schemas , the files of a schema go into a subdirectory named as the schema
+	if o.AllSchemas && len(o.DumpFile) != 0 {
+		t_dir, t_base := filepath.Split(o.DumpFile)
+		o.DumpFile = t_dir + "%d" + string(os.PathSeparator) + t_base
+	}
+	if len(o.DumpDir) != 0 && o.DumpDir[len(o.DumpDir)-1] != os.PathSeparator && len(o.DumpFile) != 0 && o.DumpFile[0] != os.PathSeparator {
+		o.DumpDir = o.DumpDir + string(os.PathSeparator)
+	}
+	if len(o.DumpDir) != 0 && o.DumpDir[len(o.DumpDir)-1] == os.PathSeparator && len(o.DumpFile) != 0 && o.DumpFile[0] == os.PathSeparator {
+		o.DumpDir = o.DumpDir[0 : len(o.DumpDir)-1]
+	}
+	// ----------------------------------------------------------------------------------
+	var cntBrowser int = o.Browsers
+	var cntReader int = o.Readers
+	// ----------------------------------------------------------------------------------
+	var conSrc []*sql.Conn
+	var conDst []*sql.Conn
+	var dbSrc *sql.DB
+	var dbDst *sql.DB
+	var srcPos statMysqlSession
+	var c_err error
+	// with all schemas mysql sessions are not connected to a schema
+	src_con_schema := ""
+	dst_con_schema := ""
+	if !o.AllSchemas {
+		src_con_schema = o.Schemas[0]
+		dst_con_schema = o.DstSchemas[0]
+	}
+	dump_start := time.Now().UTC()
+	switch o.Source.Driver {
+	case "mysql":
+		dbSrc, conSrc, srcPos, c_err = getaSynchronizedMysqlConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, src_con_schema, o.Source.Options, o.SnapshotLock, o.FromReplica)
+	case "mssql":
+		dbSrc, conSrc, srcPos, c_err = getaSynchronizedMsSqlConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, o.Source.Database, o.Source.Options)
+	case "postgres":
+		dbSrc, conSrc, srcPos, c_err = getaSynchronizedPostgresConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, o.Source.Database, o.Source.Options)
+	default:
+		return fmt.Errorf("unknown driver %s", o.Source.Driver)
+	}
+	if c_err != nil {
+		return c_err
+	}
+	defer dbSrc.Close()
+	srcUuid, srcVersion := getServerInfo(ctx, conSrc[0], o.Source.Driver)
+	// the schemas are listed in the snapshot , like the tables
+	if o.AllSchemas {
+		o.Schemas, c_err = getListSchemas(ctx, conSrc[0], o.ExcludeSchemas, o.Source.Driver)
+		if c_err != nil {
+			releaseSourceConnections(release_ctx, o.Source.Driver, conSrc)
+			return c_err
+		}
+		log.Printf("schemas to dump : %s", strings.Join(o.Schemas, " , "))
+		o.DstSchemas = o.Schemas
+	}
+	var conIdx []*sql.Conn
+	if o.DumpMode == "cpy" {
+		cntDst := o.DstWriters
+		if len(o.DeferIndexes) != 0 {
+			cntDst = cntDst + o.IndexBuilders
+		}
+		switch o.Destination.Driver {
+		case "mysql":
+			dbDst, conDst, c_err = getDstMysqlConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, cntDst, dst_con_schema, o.Destination.Options)
+		case "mssql":
+			dbDst, conDst, c_err = getDstMsSqlConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, cntDst, o.Destination.Database, o.Destination.Options)
+		case "postgres":
+			dbDst, conDst, c_err = getDstPostgresConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, cntDst, o.Destination.Database, o.Destination.Options)
+		default:
+			c_err = fmt.Errorf("unknown driver %s", o.Destination.Driver)
+		}
+		if c_err != nil {
+			releaseSourceConnections(release_ctx, o.Source.Driver, conSrc)
+			return c_err
+		}
+		defer dbDst.Close()
+		// the sessions after the writers build the deferred indexes
+		conIdx = conDst[o.DstWriters:]
+		conDst = conDst[:o.DstWriters]
+	}
+	o.Progress.emit(ProgressEvent{Stage: Progress_Connected})
+	// ----------------------------------------------------------------------------------
+	// from here a failure must give back the snapshot of the source
+	defer onFailure(&ret_err, func() { releaseSourceConnections(release_ctx, o.Source.Driver, conSrc) })
+	// ----------------------------------------------------------------------------------
+	tables2dump, l_err := getListTables(ctx, conSrc[0], o.Schemas, o.Tables, o.ExcludeTables, o.Source.Driver)
+	if l_err != nil {
+		return l_err
+	}
+	if w_err := setTableWheres(tables2dump, o.Where); w_err != nil {
+		return w_err
+	}
+	if c_err := setTableColumns(tables2dump, o.Columns, o.ExcludeColumns); c_err != nil {
+		return c_err
+	}
+	if o.DumpMode == "cpy" {
+		populateDstSchema(&tables2dump, o.Schemas, o.DstSchemas)
+	}
+	if o.AllSchemas && (o.DumpMode == "sql" || o.DumpMode == "csv") {
+		if d_err := makeSchemaDirs(tables2dump, o.DumpDir, o.DumpFile); d_err != nil {
+			return d_err
+		}
+	}
+	if mode_debug {
+		log.Print(tables2dump)
+	}
+	r, m_err := getMetadataInfo4Tables(ctx, conSrc[0], tables2dump, o.GuessPrimaryKey, o.DumpMode, o.DumpInsert, o.Source.Driver, o.Destination.Driver)
+	if m_err != nil {
+		return m_err
+	}
+	if mode_debug {
+		log.Printf("tables infos  => %v", r)
+	}
+	// ---------------------------------
+	// how each table is browsed , a big table is cut in ranges when it is not already
+	// browsed by partitions or cut in intervals
+	for i := 0; i < len(r); i++ {
+		if o.ArithmeticChunks && len(r[i].query_for_browser_minmax) > 0 {
+			r[i].arithmetic = true
+			log.Printf("table %s is cut in intervals of its pk %s", r[i].fullName, r[i].listColsPkSQL)
+		} else if o.SplitTables && cntBrowser > 1 && len(r[i].partitions) == 0 && r[i].cntRows > 10*int64(o.ChunkSize)*int64(cntBrowser) {
+			if len(r[i].query_for_split_dive) > 0 {
+				var b_err error
+				r[i].ranges, b_err = getBrowseRanges(ctx, conSrc[0], &r[i], cntBrowser)
+				if b_err != nil {
+					return b_err
+				}
+			} else {
+				log.Printf("table %s is not cut in ranges , the first column of its pk %s is not an integer , one browser reads it", r[i].fullName, r[i].listColsPkSQL)
+			}
+		}
+		if adaptive_chunks && !r[i].arithmetic {
+			r[i].sizer = newChunkSizer(r[i].fullName, mode_debug, o.ChunkTargetTime, o.ChunkTargetBytes, int64(o.ChunkMin), int64(o.ChunkMax))
+		}
+	}
+	// nothing is changed on the destination once the run is interrupted
+	if ctx.Err() != nil {
+		log.Print("dump interrupted before the destination was changed")
+		return ctx.Err()
+	}
+	if o.DumpMode == "cpy" {
+		if o.CreateTables {
+			if t_err := createTablesOnDestination(ctx, o.Source.Driver, o.Destination.Driver, conDst[0], r); t_err != nil {
+				return t_err
+			}
+		}
+		if t_err := checkTablesOnDestination(ctx, o.Source.Driver, o.Destination.Driver, conDst[0], r); t_err != nil {
+			return t_err
+		}
+	}
+	var indexes_to_create chan deferredIndex
+	if len(o.DeferIndexes) != 0 {
+		var x_err error
+		indexes_to_create, x_err = getDeferredIndexes(ctx, o.Destination.Driver, conDst[0], r, o.LoopCount)
+		if x_err != nil {
+			return x_err
+		}
+		if x_err = writeDeferredIndexesFile(o.DeferIndexes, r); x_err != nil {
+			return x_err
+		}
+		if ctx.Err() != nil {
+			log.Print("dump interrupted before the deferred indexes were dropped")
+			return ctx.Err()
+		}
+		defer onFailure(&ret_err, func() {
+			log.Printf("the deferred indexes are not all created , their ddl is in %s", o.DeferIndexes)
+		})
+		if x_err = dropDeferredIndexes(ctx, conDst[0], r); x_err != nil {
+			return x_err
+		}
+	}
+	// ---------------------------------
+	for i := 0; i < len(r); i++ {
+		r[i].insert_size = o.InsertSize
+		if o.Destination.Driver == "mssql" {
+			if r[i].cntCols*o.InsertSize >= 2100 {
+				r[i].insert_size = (2100 - 1) / r[i].cntCols
+				log.Printf("we change insertsize for %s from %d to %d ", r[i].fullName, o.InsertSize, r[i].insert_size)
+			}
+		}
+		o.Progress.emit(ProgressEvent{Stage: Progress_Metadata, Table: r[i].fullName, Rows: r[i].cntRows})
+	}
+	// ----------------------------------------------------------------------------------
+	new_manifest := func() dumpManifest {
+		return dumpManifest{
+			Driver:        o.Source.Driver,
+			ServerUuid:    srcUuid,
+			ServerVersion: srcVersion,
+			FromReplica:   o.FromReplica,
+			BinlogFile:    srcPos.FileName,
+			BinlogPos:     srcPos.FilePos,
+			GtidSet:       srcPos.Gtid,
+			StartTime:     dump_start.Format(time.RFC3339Nano),
+			EndTime:       time.Now().UTC().Format(time.RFC3339Nano),
+			DumpMode:      o.DumpMode,
+			DumpCompress:  o.DumpCompress,
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	// the ddl is read before the data , on the session used for the metadata
+	var ddl_files []string
+	defer onFailure(&ret_err, func() {
+		for _, f := range ddl_files {
+			os.Remove(f)
+		}
+	})
+	if dump_ddl {
+		for _, s := range o.Schemas {
+			var tb_names []string
+			for _, v := range r {
+				if v.dbName == s {
+					tb_names = append(tb_names, v.tbName)
+				}
+			}
+			fname_schema, fname_triggers := ddlFileNames(o.DumpDir, o.DumpFile, s)
+			if o.AllSchemas {
+				if m_err := os.MkdirAll(filepath.Dir(fname_schema), 0o755); m_err != nil {
+					return fmt.Errorf("can not create directory %s\n%w", filepath.Dir(fname_schema), m_err)
+				}
+			}
+			ddl_files = append(ddl_files, fname_schema, fname_triggers)
+			if d_err := writeMysqlSchemaDdl(ctx, conSrc[0], s, tb_names, fname_schema, fname_triggers); d_err != nil {
+				return d_err
+			}
+		}
+	}
+	if o.SchemaOnly {
+		manifest := new_manifest()
+		for _, f := range ddl_files {
+			manifest.DdlFiles = append(manifest.DdlFiles, strings.TrimPrefix(f, o.DumpDir))
+		}
+		manifest.Tables = make([]manifestTable, 0)
+		if w_err := writeDumpManifest(o.DumpDir, manifest, nil, nil); w_err != nil {
+			return w_err
+		}
+		o.Progress.emit(ProgressEvent{Stage: Progress_Done})
+		return nil
+	}
+	// ----------------------------------------------------------------------------------
+	if ctx.Err() != nil {
+		log.Print("dump interrupted before any table was read")
+		return ctx.Err()
+	}
+	// ----------------------------------------------------------------------------------
+	//
+	// browser   : scan tables on db to compute border of each chunk  and pass to a reader
+	// reader    : read chunks quickly from the db , split them into blocks , pass them to a generator
+	// generator : generate sql/csv string from blocks , and pass them to a writer
+	// writer    : receive a sql/csv string and write info a file or inject into a db
+	//
+	// the first failure of a stage cancels ctx_root , every stage stops as on a signal
+	g := newFatalGroup(ctx)
+	defer g.cancel()
+	ctx_root := g.ctx
+	var wg_brw sync.WaitGroup
+	var wg_red sync.WaitGroup
+	var wg_gen sync.WaitGroup
+	var wg_wrt sync.WaitGroup
+	var wg_idx sync.WaitGroup
+	// ----------------------------------------------------------------------------------
+	// a partitioned or a split table is browsed by several browsers at once
+	var units []browseunit
+	for t := 0; t < len(r); t++ {
+		units = append(units, browseUnits(r[t], t)...)
+	}
+	tables_to_browse := make(chan browseunit, len(units)*o.LoopCount+cntBrowser)
+	pk_chunks_to_read := make(chan tablechunk, o.Readers*200)
+	sql_to_write := make(chan insertchunk, o.Readers*400)
+	sql_generator := make(chan datachunk, o.Readers*1000)
+	if mode_debug {
+		pk_chunks_to_read = make(chan tablechunk, 5)
+		sql_to_write = make(chan insertchunk, 5)
+		sql_generator = make(chan datachunk, 5)
+	}
+	if mode_trace {
+		pk_chunks_to_read = make(chan tablechunk, 1)
+		sql_to_write = make(chan insertchunk, 1)
+		sql_generator = make(chan datachunk, 1)
+	}
+	// ------------
+	for l := 0; l < o.LoopCount; l++ {
+		for _, u := range units {
+			tables_to_browse <- u
+		}
+	}
+	for b := 0; b < cntBrowser; b++ {
+		tables_to_browse <- browseunit{table_id: -1}
+	}
+	// ------------
+	stop_progress := make(chan struct{})
+	var wg_prg sync.WaitGroup
+	queues := func() (int, int, int) {
+		return len(pk_chunks_to_read), len(sql_generator), len(sql_to_write)
+	}
+	data_start := time.Now()
+	if o.ProgressInterval > 0 {
+		for i := 0; i < len(r); i++ {
+			r[i].progress = &tableProgress{}
+		}
+		wg_prg.Add(1)
+		go func() {
+			defer wg_prg.Done()
+			progressReporter(stop_progress, o.ProgressInterval, data_start, r, o.LoopCount, queues, o.Progress)
+		}()
+	}
+	// ------------
+	for j := 0; j < cntBrowser; j++ {
+		wg_brw.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_brw.Done()
+			defer g.catch()
+			tableChunkBrowser(ctx_root, adbConn, id, tables_to_browse, r, pk_chunks_to_read, int64(o.ChunkSize))
+		}(conSrc[j], j)
+	}
+	// ------------
+	for j := 0; j < cntReader; j++ {
+		time.Sleep(10 * time.Millisecond)
+		wg_red.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_red.Done()
+			defer g.catch()
+			tableChunkReader(ctx_root, pk_chunks_to_read, sql_generator, adbConn, r, id, cntBrowser)
+		}(conSrc[j+cntBrowser], j)
+	}
+	// ------------
+	gener_cnt := o.Readers * 2
+	if mode_debug {
+		gener_cnt = 2
+	}
+	for j := 0; j < gener_cnt; j++ {
+		wg_gen.Add(1)
+		go func(id int) {
+			defer wg_gen.Done()
+			defer g.catch()
+			if o.DumpMode == "cpy" {
+				dataChunkGeneratorCpy(ctx_root, sql_generator, id, r, sql_to_write, o.Destination.Driver, cntBrowser)
+			}
+			if o.DumpMode == "sql" {
+				dataChunkGeneratorSql(ctx_root, sql_generator, id, r, sql_to_write, o.Destination.Driver, cntBrowser)
+			}
+			if o.DumpMode == "csv" {
+				dataChunkGeneratorCsv(ctx_root, sql_generator, id, r, sql_to_write, o.Destination.Driver, cntBrowser)
+			}
+			if o.DumpMode == "nul" {
+				dataChunkGeneratorNul(ctx_root, sql_generator, id, r, sql_to_write, o.Destination.Driver, cntBrowser)
+			}
+		}(j)
+	}
+	// ------------
+	writer_cnt := 0
+	writer_stats := make(chan writerStat, o.FileWriters)
+	if len(conDst) > 0 {
+		writer_cnt = len(conDst)
+		for j := 0; j < writer_cnt; j++ {
+			wg_wrt.Add(1)
+			go func(adbConn *sql.Conn, id int) {
+				defer wg_wrt.Done()
+				defer g.catch()
+				tableCopyWriter(ctx_root, sql_to_write, adbConn, id+len(conSrc), r, o.Progress)
+			}(conDst[j], j)
+		}
+	} else {
+		writer_cnt = o.FileWriters
+		for j := 0; j < o.FileWriters; j++ {
+			wg_wrt.Add(1)
+			go func(id int) {
+				defer wg_wrt.Done()
+				defer g.catch()
+				tableFileWriter(ctx_root, sql_to_write, id, r, o.DumpDir, o.DumpFile, o.DumpMode, o.DumpHeader, o.DumpCompress, o.CompressLevel, o.CompressConcur, cntBrowser, writer_stats, o.Progress)
+			}(j)
+		}
+	}
+	// ------------
+	for j := 0; j < len(conIdx); j++ {
+		wg_idx.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_idx.Done()
+			defer g.catch()
+			indexBuilder(ctx_root, adbConn, id, indexes_to_create)
+		}(conIdx[j], j)
+	}
+	// ------------
+	wg_brw.Wait()
+	log.Print("we are done with browser")
+	// ------------
+	for j := 0; j < cntReader; j++ {
+		var a_chunk tablechunk
+		a_chunk.table_id = -1
+		a_chunk.chunk_id = -1
+		a_chunk.begin_val = make([]string, 0)
+		a_chunk.end_val = make([]string, 0)
+		a_chunk.begin_equal_end = false
+		a_chunk.is_done = true
+		select {
+		case <-ctx_root.Done():
+		case pk_chunks_to_read <- a_chunk:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d empty chunk to flush readers", cntReader)
+	}
+	wg_red.Wait()
+	log.Print("we are done with reader")
+	// ------------
+	for j := 0; j < gener_cnt; j++ {
+		select {
+		case <-ctx_root.Done():
+		case sql_generator <- datachunk{table_id: -1, rows: nil}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d nil pointer to flush generators", gener_cnt)
+	}
+	wg_gen.Wait()
+	log.Print("we are done with generators")
+	// ------------
+	for j := 0; j < writer_cnt; j++ {
+		select {
+		case <-ctx_root.Done():
+		case sql_to_write <- insertchunk{table_id: 0, sql: nil}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d nil pointer to flush writers", writer_cnt)
+	}
+	wg_wrt.Wait()
+	log.Print("we are done with writers")
+	// ------------
+	// every table is loaded , the builders get their flush after the last indexes
+	for j := 0; j < len(conIdx); j++ {
+		select {
+		case <-ctx_root.Done():
+		case indexes_to_create <- deferredIndex{}:
+		}
+	}
+	wg_idx.Wait()
+	if len(conIdx) > 0 {
+		log.Print("we are done with index builders")
+	}
+	close(stop_progress)
+	wg_prg.Wait()
+	if o.ProgressInterval > 0 {
+		o.Progress.emit(ProgressEvent{Stage: Progress_Status, Status: progressStatus(data_start, r, o.LoopCount, queues)})
+	}
+	// ----------------------------------------------------------------------------------
+	if ctx_root.Err() != nil {
+		log.Print("dump interrupted , no manifest is written")
+		return g.Err()
+	}
+	if len(o.DeferIndexes) != 0 {
+		if r_err := os.Remove(o.DeferIndexes); r_err != nil {
+			log.Printf("can not remove file %s of the deferred indexes\n%s", o.DeferIndexes, r_err.Error())
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if len(conDst) == 0 && o.DumpMode != "nul" {
+		var all_stats []writerStat
+		for j := 0; j < writer_cnt; j++ {
+			all_stats = append(all_stats, <-writer_stats)
+		}
+		manifest := new_manifest()
+		for _, f := range ddl_files {
+			manifest.DdlFiles = append(manifest.DdlFiles, strings.TrimPrefix(f, o.DumpDir))
+		}
+		if w_err := writeDumpManifest(o.DumpDir, manifest, r, all_stats); w_err != nil {
+			return w_err
+		}
+	}
+	o.Progress.emit(ProgressEvent{Stage: Progress_Done})
+	return nil
+}
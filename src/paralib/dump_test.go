@@ -0,0 +1,176 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+)
+
+// ------------------------------------------------------------------------------------------
+// a driver answering the count queries of splitArithmeticChunk , the table has a row at each
+// multiple of 3 from 0 to 2999 , the arguments are the bounds of the interval
+type countDriver struct{}
+
+func (countDriver) Open(name string) (driver.Conn, error) {
+	return countConn{}, nil
+}
+
+type countConn struct{}
+
+func (countConn) Prepare(query string) (driver.Stmt, error) {
+	return countStmt{}, nil
+}
+
+func (countConn) Close() error {
+	return nil
+}
+
+func (countConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("no transaction")
+}
+
+type countStmt struct{}
+
+func (countStmt) Close() error {
+	return nil
+}
+
+func (countStmt) NumInput() int {
+	return 2
+}
+
+func (countStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("no exec")
+}
+
+func (countStmt) Query(args []driver.Value) (driver.Rows, error) {
+	lo, _ := strconv.ParseInt(args[0].(string), 10, 64)
+	hi, _ := strconv.ParseInt(args[1].(string), 10, 64)
+	return &countRows{cnt: countTestRows(lo, hi)}, nil
+}
+
+type countRows struct {
+	cnt  int64
+	done bool
+}
+
+func (r *countRows) Columns() []string {
+	return []string{"cnt"}
+}
+
+func (r *countRows) Close() error {
+	return nil
+}
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.cnt
+	return nil
+}
+
+func countTestRows(lo int64, hi int64) int64 {
+	var cnt int64
+	for v := int64(0); v < 3000; v = v + 3 {
+		if v >= lo && v < hi {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func init() {
+	sql.Register("paralib_count", countDriver{})
+}
+
+// ------------------------------------------------------------------------------------------
+func TestSplitArithmeticChunk(t *testing.T) {
+	db, err := sql.Open("paralib_count", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	a_table := metadataTable{fullName: "app.t", param_indices_interval_lo_qry: []int{0}, param_indices_interval_up_qry: []int{0}}
+	a_table.query_for_reader_count = "select count(*) from ( select 1 from app.t where id >= ? and id < ? limit %d ) c"
+	tests := []struct {
+		name        string
+		begin       int64
+		end         int64
+		split_limit int64
+		min_parts   int
+	}{
+		{"narrow interval not counted", 0, 90, 100, 1},
+		{"few rows not split", 0, 300, 200, 1},
+		{"dense", 0, 3000, 100, 10},
+		{"sparse", 0, 1000000, 100, 10},
+		{"negative", -1000000, 3000, 50, 20},
+	}
+	for _, tt := range tests {
+		a_chunk := tablechunk{table_id: 0, chunk_id: 1, begin_val: []string{strconv.FormatInt(tt.begin, 10)}, end_val: []string{strconv.FormatInt(tt.end, 10)}, split_limit: tt.split_limit}
+		parts := splitArithmeticChunk(context.Background(), conn, &a_table, a_chunk)
+		if len(parts) < tt.min_parts {
+			t.Errorf("%s : %d parts , want at least %d", tt.name, len(parts), tt.min_parts)
+		}
+		// the parts follow each other from begin to end , none has too many rows
+		expect_begin := tt.begin
+		for _, a_part := range parts {
+			lo, _ := strconv.ParseInt(a_part.begin_val[0], 10, 64)
+			hi, _ := strconv.ParseInt(a_part.end_val[0], 10, 64)
+			if lo != expect_begin || hi <= lo {
+				t.Errorf("%s : part %d -> %d does not follow %d", tt.name, lo, hi, expect_begin)
+			}
+			if cnt := countTestRows(lo, hi); cnt > tt.split_limit {
+				t.Errorf("%s : part %d -> %d has %d rows , more than %d", tt.name, lo, hi, cnt, tt.split_limit)
+			}
+			expect_begin = hi
+		}
+		if expect_begin != tt.end {
+			t.Errorf("%s : parts end at %d , want %d", tt.name, expect_begin, tt.end)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestBrowseUnitsChunkIds(t *testing.T) {
+	tests := []struct {
+		name    string
+		a_table metadataTable
+		units   int
+	}{
+		{"table", metadataTable{}, 1},
+		{"partitions", metadataTable{partitions: []string{"p0", "p1", "p2"}}, 3},
+		{"ranges", metadataTable{ranges: [][]string{{"1"}, {"500"}, {"900"}}}, 3},
+	}
+	for _, tt := range tests {
+		var first_ids []int64
+		for table_id := 0; table_id < 3; table_id++ {
+			units := browseUnits(tt.a_table, table_id)
+			if len(units) != tt.units {
+				t.Fatalf("%s : %d units , want %d", tt.name, len(units), tt.units)
+			}
+			for _, u := range units {
+				first_ids = append(first_ids, u.firstChunkId())
+			}
+		}
+		// every unit of every table starts its ids far from the other ones
+		for i := range first_ids {
+			for j := range first_ids {
+				diff := first_ids[i] - first_ids[j]
+				if i != j && diff < 100000000 && diff > -100000000 {
+					t.Errorf("%s : units %d and %d start at %d and %d", tt.name, i, j, first_ids[i], first_ids[j])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package paralib
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------
+// a table pattern is matched against schema.table
+//
+//	/regex/       a regular expression on schema.table
+//	schema.table  shell globs ( * ? [...] ) on the schema and on the table
+//	table         a shell glob on the table , in every schema
+//
+// a name without glob characters is an exact name , a table name alone matches the tables
+// of this name in all the schemas read , for the tables to include and to exclude
+type tablePattern struct {
+	text   string
+	regex  *regexp.Regexp
+	schema string
+	table  string
+}
+
+func parseTablePattern(text string) (tablePattern, error) {
+	ret_val := tablePattern{text: text}
+	if len(text) > 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+		re, err := regexp.Compile(text[1 : len(text)-1])
+		if err != nil {
+			return ret_val, fmt.Errorf("invalid table regex %s\n%w", text, err)
+		}
+		ret_val.regex = re
+		return ret_val, nil
+	}
+	ret_val.table = text
+	if p := strings.IndexByte(text, '.'); p >= 0 {
+		ret_val.schema = text[:p]
+		ret_val.table = text[p+1:]
+	}
+	for _, glob := range []string{ret_val.schema, ret_val.table} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return ret_val, fmt.Errorf("invalid table pattern %s\n%w", text, err)
+		}
+	}
+	if len(ret_val.table) == 0 {
+		return ret_val, fmt.Errorf("invalid table pattern %s , the table is empty", text)
+	}
+	return ret_val, nil
+}
+
+func parseTablePatterns(texts []string) ([]tablePattern, error) {
+	var ret_val []tablePattern
+	for _, text := range texts {
+		a_pat, err := parseTablePattern(text)
+		if err != nil {
+			return nil, err
+		}
+		ret_val = append(ret_val, a_pat)
+	}
+	return ret_val, nil
+}
+
+func (p tablePattern) match(dbName string, tbName string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(dbName + "." + tbName)
+	}
+	if len(p.schema) > 0 {
+		if ok, _ := path.Match(p.schema, dbName); !ok {
+			return false
+		}
+	}
+	ok, _ := path.Match(p.table, tbName)
+	return ok
+}
+
+// ------------------------------------------------------------------------------------------
+// keep the tables matching one of tab2include ( all when empty ) and none of tab2exclude ,
+// the decision is logged for each table . a pattern of tab2include matching no table is a
+// failure , it is most of the time a typo
+func filterTables(tables []aTable, tab2include []string, tab2exclude []string) ([]aTable, error) {
+	pat_include, i_err := parseTablePatterns(tab2include)
+	if i_err != nil {
+		return nil, i_err
+	}
+	pat_exclude, e_err := parseTablePatterns(tab2exclude)
+	if e_err != nil {
+		return nil, e_err
+	}
+	var result []aTable
+	include_cnt := make([]int, len(pat_include))
+	for _, tab := range tables {
+		included_by := ""
+		if len(pat_include) == 0 {
+			included_by = "*"
+		}
+		for n, pat := range pat_include {
+			if pat.match(tab.dbName, tab.tbName) {
+				include_cnt[n]++
+				if len(included_by) == 0 {
+					included_by = pat.text
+				}
+			}
+		}
+		if len(included_by) == 0 {
+			log.Printf("%s.%s is skipped because no table pattern matches", tab.dbName, tab.tbName)
+			continue
+		}
+		excluded_by := ""
+		for _, pat := range pat_exclude {
+			if pat.match(tab.dbName, tab.tbName) {
+				excluded_by = pat.text
+				break
+			}
+		}
+		if len(excluded_by) > 0 {
+			log.Printf("%s.%s is excluded because match with %s", tab.dbName, tab.tbName, excluded_by)
+			continue
+		}
+		log.Printf("%s.%s is included because match with %s", tab.dbName, tab.tbName, included_by)
+		result = append(result, tab)
+	}
+	for n, pat := range pat_include {
+		if include_cnt[n] == 0 {
+			return nil, fmt.Errorf("no table matches %s", pat.text)
+		}
+	}
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// a schema pattern is /regex/ or a shell glob on the name of the schema
+type schemaPattern struct {
+	text  string
+	regex *regexp.Regexp
+}
+
+func parseSchemaPatterns(texts []string) ([]schemaPattern, error) {
+	var ret_val []schemaPattern
+	for _, text := range texts {
+		a_pat := schemaPattern{text: text}
+		if len(text) > 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+			re, err := regexp.Compile(text[1 : len(text)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema regex %s\n%w", text, err)
+			}
+			a_pat.regex = re
+		} else if _, err := path.Match(text, ""); err != nil {
+			return nil, fmt.Errorf("invalid schema pattern %s\n%w", text, err)
+		}
+		if len(text) == 0 {
+			return nil, fmt.Errorf("invalid schema pattern , the schema is empty")
+		}
+		ret_val = append(ret_val, a_pat)
+	}
+	return ret_val, nil
+}
+
+func (p schemaPattern) match(dbName string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(dbName)
+	}
+	ok, _ := path.Match(p.text, dbName)
+	return ok
+}
+
+// ------------------------------------------------------------------------------------------
+// keep the schemas matching none of sch2exclude , the excluded ones are logged
+func filterSchemas(schemas []string, sch2exclude []string) ([]string, error) {
+	pat_exclude, e_err := parseSchemaPatterns(sch2exclude)
+	if e_err != nil {
+		return nil, e_err
+	}
+	var result []string
+	for _, sch := range schemas {
+		excluded_by := ""
+		for _, pat := range pat_exclude {
+			if pat.match(sch) {
+				excluded_by = pat.text
+				break
+			}
+		}
+		if len(excluded_by) > 0 {
+			log.Printf("schema %s is excluded because match with %s", sch, excluded_by)
+			continue
+		}
+		result = append(result, sch)
+	}
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// a table filter is pattern:expression , the expression is a sql condition added with an and
+// to the queries reading the tables matching the table pattern . it is sent as it is to the
+// database , so it must be valid for the driver
+type tableWhere struct {
+	pattern tablePattern
+	expr    string
+}
+
+// split pattern:value , the pattern of a regex ends with /:
+func cutTablePattern(text string) (tablePattern, string, bool) {
+	p := strings.IndexByte(text, ':')
+	if strings.HasPrefix(text, "/") {
+		p = strings.Index(text[1:], "/:")
+		if p >= 0 {
+			p = p + 2
+		}
+	}
+	if p <= 0 || len(strings.TrimSpace(text[p+1:])) == 0 {
+		return tablePattern{}, "", false
+	}
+	a_pat, err := parseTablePattern(text[:p])
+	if err != nil {
+		return tablePattern{}, "", false
+	}
+	return a_pat, strings.TrimSpace(text[p+1:]), true
+}
+
+func parseTableWheres(texts []string) ([]tableWhere, error) {
+	var ret_val []tableWhere
+	for _, text := range texts {
+		a_pat, expr, ok := cutTablePattern(text)
+		if !ok {
+			return nil, fmt.Errorf("invalid table filter %s , must be table:expression", text)
+		}
+		ret_val = append(ret_val, tableWhere{pattern: a_pat, expr: expr})
+	}
+	return ret_val, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// set the filter of each table , the expressions of all the matching filters are and-ed . a
+// filter matching no table is a failure , like a pattern of the tables to include
+func setTableWheres(tables []aTable, tab2filter []string) error {
+	wheres, w_err := parseTableWheres(tab2filter)
+	if w_err != nil {
+		return w_err
+	}
+	exprs := make([][]string, len(tables))
+	for _, w := range wheres {
+		cnt := 0
+		for n := range tables {
+			if w.pattern.match(tables[n].dbName, tables[n].tbName) {
+				exprs[n] = append(exprs[n], w.expr)
+				cnt++
+			}
+		}
+		if cnt == 0 {
+			return fmt.Errorf("no table matches the filter %s", w.pattern.text)
+		}
+	}
+	for n := range tables {
+		if len(exprs[n]) == 1 {
+			tables[n].where = exprs[n][0]
+		}
+		if len(exprs[n]) > 1 {
+			tables[n].where = "( " + strings.Join(exprs[n], " ) and ( ") + " )"
+		}
+		if len(tables[n].where) > 0 {
+			log.Printf("%s.%s is filtered with %s", tables[n].dbName, tables[n].tbName, tables[n].where)
+		}
+	}
+	return nil
+}
+
+// the filter of a table added to the conditions on the primary key
+func sqlTableWhere(where string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return " and ( " + where + " )"
+}
+
+// ------------------------------------------------------------------------------------------
+// a column list is pattern:col1,col2 , each column is a shell glob on the column name . the
+// columns read from a table are the ones matching an include list ( all when there is none )
+// and no exclude list , the columns of the primary key are always read
+type tableColumns struct {
+	pattern tablePattern
+	columns []string
+}
+
+type columnFilter struct {
+	include []string
+	exclude []string
+}
+
+func parseTableColumns(texts []string) ([]tableColumns, error) {
+	var ret_val []tableColumns
+	for _, text := range texts {
+		a_pat, list, ok := cutTablePattern(text)
+		if !ok {
+			return nil, fmt.Errorf("invalid column list %s , must be table:col1,col2", text)
+		}
+		a_cols := tableColumns{pattern: a_pat}
+		for _, col := range strings.Split(list, ",") {
+			col = strings.TrimSpace(col)
+			if _, err := path.Match(col, ""); err != nil || len(col) == 0 {
+				return nil, fmt.Errorf("invalid column %q in column list %s", col, text)
+			}
+			a_cols.columns = append(a_cols.columns, col)
+		}
+		ret_val = append(ret_val, a_cols)
+	}
+	return ret_val, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// set the column filter of each table , a list matching no table is a failure
+func setTableColumns(tables []aTable, col2include []string, col2exclude []string) error {
+	for _, lst := range []struct {
+		texts   []string
+		include bool
+	}{{col2include, true}, {col2exclude, false}} {
+		all_cols, c_err := parseTableColumns(lst.texts)
+		if c_err != nil {
+			return c_err
+		}
+		for _, c := range all_cols {
+			cnt := 0
+			for n := range tables {
+				if !c.pattern.match(tables[n].dbName, tables[n].tbName) {
+					continue
+				}
+				cnt++
+				if lst.include {
+					tables[n].columns.include = append(tables[n].columns.include, c.columns...)
+				} else {
+					tables[n].columns.exclude = append(tables[n].columns.exclude, c.columns...)
+				}
+			}
+			if cnt == 0 {
+				return fmt.Errorf("no table matches the column list %s", c.pattern.text)
+			}
+		}
+	}
+	return nil
+}
+
+func (f columnFilter) isEmpty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+func matchColumn(globs []string, colName string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, colName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// the columns kept , in the order of the table . the skipped ones are logged
+func (f columnFilter) project(tableName string, cols []columnInfo, primaryKey []string) []columnInfo {
+	var result []columnInfo
+	var skipped []string
+	for _, col := range cols {
+		keep := (len(f.include) == 0 || matchColumn(f.include, col.colName)) && !matchColumn(f.exclude, col.colName)
+		for _, pk := range primaryKey {
+			if !keep && pk == col.colName {
+				log.Printf("column %s of %s is kept because it is in the primary key", col.colName, tableName)
+				keep = true
+			}
+		}
+		if keep {
+			result = append(result, col)
+		} else {
+			skipped = append(skipped, col.colName)
+		}
+	}
+	if len(skipped) > 0 {
+		log.Printf("columns %s of %s are not read", strings.Join(skipped, " , "), tableName)
+	}
+	return result
+}
+
+// the columns of dst that are also in src , to compare a projection with the destination
+func projectColumns(src []columnInfo, dst []columnInfo) []columnInfo {
+	var result []columnInfo
+	for _, d_col := range dst {
+		for _, s_col := range src {
+			if s_col.colName == d_col.colName {
+				result = append(result, d_col)
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package paralib
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ------------------------------------------------------------------------------------------
+func TestTablePatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		db_name string
+		tb_name string
+		want    bool
+	}{
+		// a match at the start of the name
+		{"orders", "app", "orders", true},
+		{"orders", "app", "orders_old", false},
+		{"orders", "app", "old_orders", false},
+		{"ord*", "app", "orders", true},
+		// a bare name is matched in every schema
+		{"orders", "shop", "orders", true},
+		{"app.orders", "shop", "orders", false},
+		{"app.orders", "app", "orders", true},
+		{"app.*_archive", "app", "orders_archive", true},
+		{"app.*_archive", "app", "orders_archive_old", false},
+		{"app.*_archive", "shop", "orders_archive", false},
+		{"*.orders", "shop", "orders", true},
+		{"app.order?", "app", "orders", true},
+		{"app.order[sx]", "app", "orderx", true},
+		// the dot of a regex is any character , the name is schema.table
+		{"/^app\\.orders$/", "app", "orders", true},
+		{"/^app.orders$/", "appxorders", "", false},
+		{"/^app.orders/", "app", "orders_2024", true},
+		{"/^ap.\\.orders$/", "apx", "orders", true},
+		{"/_archive$/", "app", "orders_archive", true},
+		{"/^orders$/", "app", "orders", false},
+	}
+	for _, tt := range tests {
+		a_pat, err := parseTablePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("pattern %s : %s", tt.pattern, err)
+		}
+		if got := a_pat.match(tt.db_name, tt.tb_name); got != tt.want {
+			t.Errorf("pattern %s on %s.%s = %v , want %v", tt.pattern, tt.db_name, tt.tb_name, got, tt.want)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestParseTablePatternInvalid(t *testing.T) {
+	for _, text := range []string{"/ord(ers/", "app.ord[ers", "app.", "app.["} {
+		if _, err := parseTablePattern(text); err == nil {
+			t.Errorf("pattern %s is accepted , want an error", text)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestFilterTables(t *testing.T) {
+	tables := []aTable{
+		{dbName: "app", tbName: "orders"},
+		{dbName: "app", tbName: "orders_archive"},
+		{dbName: "app", tbName: "users"},
+		{dbName: "shop", tbName: "orders"},
+		{dbName: "shop", tbName: "items_archive"},
+	}
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		failed  bool
+	}{
+		{"all", nil, nil, []string{"app.orders", "app.orders_archive", "app.users", "shop.orders", "shop.items_archive"}, false},
+		{"bare name in every schema", []string{"orders"}, nil, []string{"app.orders", "shop.orders"}, false},
+		{"schema and glob", []string{"app.*_archive"}, nil, []string{"app.orders_archive"}, false},
+		{"regex with a dot", []string{"/^shop\\./"}, nil, []string{"shop.orders", "shop.items_archive"}, false},
+		{"exclude overrides include", []string{"app.*"}, []string{"*_archive"}, []string{"app.orders", "app.users"}, false},
+		{"exclude of an included name", []string{"orders", "users"}, []string{"shop.orders"}, []string{"app.orders", "app.users"}, false},
+		{"exclude only", nil, []string{"/_archive$/"}, []string{"app.orders", "app.users", "shop.orders"}, false},
+		{"include matching no table", []string{"orders", "invoices"}, nil, nil, true},
+		{"invalid regex", []string{"/ord(/"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		result, err := filterTables(tables, tt.include, tt.exclude)
+		if tt.failed {
+			if err == nil {
+				t.Errorf("%s : no error , want one", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s : %s", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, tab := range result {
+			got = append(got, tab.dbName+"."+tab.tbName)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v , want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestParseTableWheres(t *testing.T) {
+	tests := []struct {
+		text    string
+		pattern string
+		expr    string
+		failed  bool
+	}{
+		{"orders:id > 10", "orders", "id > 10", false},
+		{"app.orders: created_at >= '2024-01-01 00:00:00' ", "app.orders", "created_at >= '2024-01-01 00:00:00'", false},
+		{"/^app\\.ord.*/:status = 'open'", "/^app\\.ord.*/", "status = 'open'", false},
+		{"/a:b/:x = 1", "/a:b/", "x = 1", false},
+		{"orders", "", "", true},
+		{":id > 10", "", "", true},
+		{"orders:  ", "", "", true},
+	}
+	for _, tt := range tests {
+		wheres, err := parseTableWheres([]string{tt.text})
+		if tt.failed {
+			if err == nil {
+				t.Errorf("filter %q is accepted , want an error", tt.text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("filter %q : %s", tt.text, err)
+			continue
+		}
+		if wheres[0].pattern.text != tt.pattern || wheres[0].expr != tt.expr {
+			t.Errorf("filter %q = %q , %q , want %q , %q", tt.text, wheres[0].pattern.text, wheres[0].expr, tt.pattern, tt.expr)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func TestParseTableColumns(t *testing.T) {
+	tests := []struct {
+		text    string
+		pattern string
+		columns []string
+		failed  bool
+	}{
+		{"orders:id,amount", "orders", []string{"id", "amount"}, false},
+		{"app.*: id , created_* ", "app.*", []string{"id", "created_*"}, false},
+		{"/^app\\./:blob_?", "/^app\\./", []string{"blob_?"}, false},
+		{"orders:id,,amount", "", nil, true},
+		{"orders:[id", "", nil, true},
+		{"orders", "", nil, true},
+	}
+	for _, tt := range tests {
+		lists, err := parseTableColumns([]string{tt.text})
+		if tt.failed {
+			if err == nil {
+				t.Errorf("column list %q is accepted , want an error", tt.text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("column list %q : %s", tt.text, err)
+			continue
+		}
+		if lists[0].pattern.text != tt.pattern || !reflect.DeepEqual(lists[0].columns, tt.columns) {
+			t.Errorf("column list %q = %q , %v , want %q , %v", tt.text, lists[0].pattern.text, lists[0].columns, tt.pattern, tt.columns)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+//
+// in cpy mode the secondary indexes of the destination can be dropped before the load and
+// created again when a table is loaded , their ddl is saved in a file before any drop so
+// they can be restored by hand after a crash
+//
+// a table is loaded when its pending work is done . the work is counted along the pipeline ,
+// a browse of the table , the chunks of the browser , the blocks of the readers ( one insert
+// each for the writers ) . a unit is added before its parent is done , so the count can only
+// be zero at the end
+type deferredIndex struct {
+	table  string
+	name   string
+	create string
+}
+
+type deferredIndexes struct {
+	pending atomic.Int64
+	table   string
+	indexes []deferredIndex
+	drops   []string
+	ready   chan deferredIndex
+}
+
+func (d *deferredIndexes) add() {
+	if d != nil {
+		d.pending.Add(1)
+	}
+}
+
+// the last done of a table gives its indexes to the builders , ready is large enough for
+// all the indexes so it never blocks
+func (d *deferredIndexes) done() {
+	if d == nil || d.pending.Add(-1) != 0 {
+		return
+	}
+	log.Printf("table %s is loaded , %d indexes to create", d.table, len(d.indexes))
+	for _, ix := range d.indexes {
+		d.ready <- ix
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// collect the secondary indexes of the tables on destination , each browse unit of a table is
+// browsed loopCount times . ready gets the indexes of each table when it is loaded
+func getDeferredIndexes(run_ctx context.Context, dstdriver string, adbConn *sql.Conn, infTables []metadataTable, loopCount int) (chan deferredIndex, error) {
+	var all_idx []*deferredIndexes
+	cnt_idx := 0
+	for n := range infTables {
+		a_table := &deferredIndexes{table: infTables[n].dstDbName + "." + infTables[n].tbName}
+		a_table.pending.Store(int64(loopCount * len(browseUnits(infTables[n], n))))
+		ctx, cancel := phaseContext(run_ctx, timeout_metadata, 5*time.Second)
+		q_rows, q_err := adbConn.QueryContext(ctx, getSqlDialect(dstdriver).SecondaryIndexesQuery(), infTables[n].dstDbName, infTables[n].tbName)
+		if q_err != nil {
+			cancel()
+			return nil, fmt.Errorf("can not collect indexes of table %s on destination\n%w", a_table.table, phaseError(ctx, q_err, a_table.table))
+		}
+		for q_rows.Next() {
+			var ix_name, ix_drop string
+			var ix_create sql.NullString
+			if err := q_rows.Scan(&ix_name, &ix_create, &ix_drop); err != nil {
+				q_rows.Close()
+				cancel()
+				return nil, fmt.Errorf("can not scan index informations of table %s\n%w", a_table.table, err)
+			}
+			if !ix_create.Valid {
+				q_rows.Close()
+				cancel()
+				return nil, fmt.Errorf("index %s of table %s can not be created again , it can not be deferred", ix_name, a_table.table)
+			}
+			a_table.indexes = append(a_table.indexes, deferredIndex{table: a_table.table, name: ix_name, create: ix_create.String})
+			a_table.drops = append(a_table.drops, ix_drop)
+		}
+		r_err := q_rows.Err()
+		q_rows.Close()
+		cancel()
+		if r_err != nil {
+			return nil, fmt.Errorf("can not collect indexes of table %s on destination\n%w", a_table.table, phaseError(ctx, r_err, a_table.table))
+		}
+		if len(a_table.indexes) > 0 {
+			infTables[n].deferred = a_table
+			all_idx = append(all_idx, a_table)
+			cnt_idx += len(a_table.indexes)
+		}
+	}
+	ready := make(chan deferredIndex, cnt_idx)
+	for _, a_table := range all_idx {
+		a_table.ready = ready
+	}
+	log.Printf("%d indexes of %d tables are deferred", cnt_idx, len(all_idx))
+	return ready, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// the file is not overwritten , it may be the only copy of the indexes of a crashed copy
+func writeDeferredIndexesFile(fname string, infTables []metadataTable) error {
+	f, o_err := os.OpenFile(fname, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if o_err != nil {
+		return fmt.Errorf("can not create file %s for the deferred indexes , a file of a previous copy must be restored and removed\n%w", fname, o_err)
+	}
+	var b strings.Builder
+	b.WriteString("-- secondary indexes dropped before the copy , this file is removed when all of them are created again\n")
+	b.WriteString("-- after a failure , run the statements of the indexes that are missing\n")
+	for _, a_table := range infTables {
+		if a_table.deferred == nil {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n-- %s\n", a_table.deferred.table))
+		for _, ix := range a_table.deferred.indexes {
+			b.WriteString(ix.create + ";\n")
+		}
+	}
+	_, w_err := f.WriteString(b.String())
+	if w_err == nil {
+		w_err = f.Sync()
+	}
+	c_err := f.Close()
+	if w_err == nil {
+		w_err = c_err
+	}
+	if w_err != nil {
+		return fmt.Errorf("can not write file %s for the deferred indexes\n%w", fname, w_err)
+	}
+	log.Printf("ddl of the deferred indexes saved in %s", fname)
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+func dropDeferredIndexes(run_ctx context.Context, adbConn *sql.Conn, infTables []metadataTable) error {
+	for _, a_table := range infTables {
+		if a_table.deferred == nil {
+			continue
+		}
+		for n, query := range a_table.deferred.drops {
+			ctx, cancel := phaseContext(run_ctx, timeout_write, 0)
+			_, e_err := adbConn.ExecContext(ctx, query)
+			cancel()
+			if e_err != nil {
+				return fmt.Errorf("can not drop index %s of table %s on destination\n%w", a_table.deferred.indexes[n].name, a_table.deferred.table, phaseError(ctx, e_err, a_table.deferred.table))
+			}
+		}
+		log.Printf("%d indexes of table %s are dropped", len(a_table.deferred.drops), a_table.deferred.table)
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+// an empty name flushes the builder
+func indexBuilder(ctx_root context.Context, adbConn *sql.Conn, id int, indexes chan deferredIndex) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("indexBuilder[%02d] start\n", id)
+	}
+	for {
+		var ix deferredIndex
+		select {
+		case <-ctx_root.Done():
+			return
+		case ix = <-indexes:
+		}
+		if len(ix.name) == 0 {
+			break
+		}
+		start := time.Now()
+		ctx, cancel := phaseContext(ctx_root, timeout_write, 0)
+		_, e_err := adbConn.ExecContext(ctx, ix.create)
+		cancel()
+		if e_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			log.Printf("error with :\n%s", ix.create)
+			failf("thread %d , can not create index %s of table %s\n%w", id, ix.name, ix.table, phaseError(ctx, e_err, ix.table))
+		}
+		log.Printf("index %s of table %s is created in %s", ix.name, ix.table, time.Since(start).Round(time.Millisecond))
+	}
+	if mode_debug {
+		log.Printf("indexBuilder[%02d] finish\n", id)
+	}
+}
@@ -0,0 +1,594 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+type columnInfo struct {
+	colName      string
+	colType      string
+	colSqlType   string
+	dtPrec       int
+	nuPrec       int
+	isNullable   bool
+	mustBeQuote  bool
+	haveFract    bool
+	isKindChar   bool
+	isKindBinary bool
+	isKindFloat  bool
+	isKindDate   bool
+}
+
+type indexInfo struct {
+	idxName     string
+	cardinality int64
+	columns     []columnInfo
+}
+
+type aTable struct {
+	dbName    string
+	tbName    string
+	dstDbName string
+	where     string
+	columns   columnFilter
+}
+
+// the queries reading the rows of a table , the ones of a partition read from the partition
+// only
+type readQueries struct {
+	query_for_browser_first       string
+	query_for_browser_next        string
+	query_for_browser_first_lower string
+	query_for_browser_first_range string
+	query_for_browser_next_range  string
+	query_for_browser_minmax      string
+	query_for_reader_count        string
+	query_for_reader_equality     string
+	query_for_reader_interval     string
+}
+
+type metadataTable struct {
+	dbName            string
+	dstDbName         string
+	tbName            string
+	isEmpty           bool
+	cntRows           int64
+	sizeBytes         int64
+	storageEng        string
+	cntCols           int
+	cntPkCols         int
+	columnInfos       []columnInfo
+	dstColumnInfos    []columnInfo
+	primaryKey        []string
+	primaryKeyEnum    []string
+	Indexes           []indexInfo
+	fakePrimaryKey    bool
+	withTrigger       bool
+	onError           int
+	fullName          string
+	where             string
+	projected         bool
+	deferred          *deferredIndexes
+	partitions        []string
+	partition_queries map[string]readQueries
+	readQueries
+	arithmetic                       bool
+	ranges                           [][]string
+	sizer                            *chunkSizer
+	progress                         *tableProgress
+	query_for_split_minmax           string
+	query_for_split_dive             string
+	listColsSQL                      string
+	listColsPkSQL                    string
+	listColsPkFetchSQL               string
+	listColsPkOrderSQL               string
+	listColsPkOrderDescSQL           string
+	listColsCSV                      string
+	param_indices_browser_next_qry   []int
+	param_indices_equality_qry       []int
+	query_for_dst_reader_interval    string
+	param_indices_interval_lo_qry    []int
+	param_indices_interval_up_qry    []int
+	query_for_reader_upper_bound     string
+	query_for_dst_reader_upper_bound string
+	param_indices_upper_bound_qry    []int
+	query_for_reader_lower_bound     string
+	query_for_dst_reader_lower_bound string
+	param_indices_lower_bound_qry    []int
+	query_for_insert                 []string
+	query_for_insert_size            int
+	query_for_update                 []string
+	query_for_update_size            int
+	query_for_delete                 []string
+	query_for_delete_size            int
+	query_for_insert_head            string
+	insert_size                      int
+}
+
+// ------------------------------------------------------------------------------------------
+func getMysqlBasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	var result metadataTable
+	result.dbName = dbName
+	result.tbName = tableName
+	result.fakePrimaryKey = false
+	result.onError = 0
+	result.isEmpty = true
+	result.withTrigger = false
+
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	p_err := adbConn.PingContext(ctx)
+	if p_err != nil {
+		return result, fmt.Errorf("can not ping\n%w", phaseError(ctx, p_err, dbName+"."+tableName))
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err := adbConn.QueryContext(ctx, "select coalesce(data_length + index_length,-1),coalesce(TABLE_ROWS,-1),coalesce(ENGINE,'UNKNOW'),TABLE_TYPE from information_schema.tables WHERE table_schema = ? AND table_name = ?     ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.tables for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	typeTable := "DO_NOT_EXIST"
+	for q_rows.Next() {
+		err := q_rows.Scan(&result.sizeBytes, &result.cntRows, &result.storageEng, &typeTable)
+		if err != nil {
+			return result, fmt.Errorf("can not query information_schema.tables for %s.%s\n%w", dbName, tableName, err)
+		}
+		if result.storageEng != "InnoDB" {
+			result.onError = result.onError | 4
+		}
+		if typeTable != "BASE TABLE" {
+			result.onError = result.onError | 8
+		}
+	}
+	if typeTable == "DO_NOT_EXIST" {
+		result.onError = result.onError | 16
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err = adbConn.QueryContext(ctx, "select COLUMN_NAME , DATA_TYPE,IS_NULLABLE,IFNULL(DATETIME_PRECISION,-9999),IFNULL(NUMERIC_PRECISION,-9999),COLUMN_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? order by ORDINAL_POSITION ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.columns for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_col columnInfo
+		var a_str string
+		err := q_rows.Scan(&a_col.colName, &a_col.colType, &a_str, &a_col.dtPrec, &a_col.nuPrec, &a_col.colSqlType)
+		if err != nil {
+			return result, fmt.Errorf("can not scan columns informations\n%w", err)
+		}
+		a_col.isNullable = (a_str == "YES")
+		a_col.isKindChar = (a_col.colType == "char" || a_col.colType == "longtext" || a_col.colType == "mediumtext" || a_col.colType == "text" || a_col.colType == "tinytext" || a_col.colType == "varchar" || a_col.colType == "enum")
+		a_col.isKindBinary = (a_col.colType == "varbinary" || a_col.colType == "binary" || a_col.colType == "tinyblob" || a_col.colType == "blob" || a_col.colType == "longblob" || a_col.colType == "bit")
+		a_col.isKindDate = a_col.colType == "date" || a_col.colType == "datetime" || a_col.colType == "time" || a_col.colType == "timestamp"
+		a_col.mustBeQuote = a_col.isKindChar || a_col.isKindBinary || a_col.isKindDate
+		a_col.isKindFloat = (a_col.colType == "float" || a_col.colType == "double")
+		a_col.haveFract = (a_col.colType == "datetime" || a_col.colType == "timestamp" || a_col.colType == "time") && (a_col.dtPrec > 0)
+		re := regexp.MustCompile(`int(\([0-9]*\))`)
+		a_col.colSqlType = re.ReplaceAllString(a_col.colSqlType, "int")
+		result.columnInfos = append(result.columnInfos, a_col)
+	}
+
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err = adbConn.QueryContext(ctx, "select COLUMN_NAME  from INFORMATION_SCHEMA.STATISTICS WHERE table_schema = ? AND table_name = ?   and INDEX_NAME = 'PRIMARY' order by SEQ_IN_INDEX    ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query INFORMATION_SCHEMA.STATISTICS  to get primary key info for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return result, fmt.Errorf("can not scan primary key informations\n%w", err)
+		}
+		result.primaryKey = append(result.primaryKey, a_str)
+	}
+
+	// ---------------------------------------------------------------------------------
+	result.fullName = fmt.Sprintf("`%s`.`%s`", result.dbName, result.tbName)
+	result.cntCols = len(result.columnInfos)
+	// ---------------------------------------------------------------------------------
+	if result.onError == 0 {
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+
+		q_rows, q_err = adbConn.QueryContext(ctx, fmt.Sprintf("select 1 from %s limit 1", result.fullName))
+		if q_err != nil {
+			return result, fmt.Errorf("can not query the table %s for one row\n%w", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan a simple value from %s\n%w", result.fullName, err)
+			}
+			result.isEmpty = false
+		}
+		// -------------------------------------------------------------------------
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+
+		q_rows, q_err = adbConn.QueryContext(ctx, "select count(*) from INFORMATION_SCHEMA.TRIGGERS WHERE EVENT_OBJECT_SCHEMA = ? AND EVENT_OBJECT_TABLE = ?", dbName, tableName)
+		if q_err != nil {
+			return result, fmt.Errorf("can not query INFORMATION_SCHEMA.TRIGGERS to detect trigger for table %s (%w)", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan count from INFORMATION_SCHEMA.TRIGGERS for table %s\n%w", result.fullName, err)
+			}
+			result.withTrigger = a_bigint > 0
+		}
+		// -------------------------------------------------------------------------
+	}
+	// ---------------------------------------------------------------------------------
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// partitions of a mysql table in their order , each one is browsed as a table
+func getMysqlPartitions(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) ([]string, error) {
+	var result []string
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, "select PARTITION_NAME from INFORMATION_SCHEMA.PARTITIONS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME is not null group by PARTITION_NAME , PARTITION_ORDINAL_POSITION order by PARTITION_ORDINAL_POSITION", dbName, tableName)
+	if q_err != nil {
+		return nil, fmt.Errorf("can not query INFORMATION_SCHEMA.PARTITIONS for table %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	defer q_rows.Close()
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan partition informations for table %s.%s\n%w", dbName, tableName, err)
+		}
+		result = append(result, a_str)
+	}
+	if r_err := q_rows.Err(); r_err != nil {
+		return nil, fmt.Errorf("can not query INFORMATION_SCHEMA.PARTITIONS for table %s.%s\n%w", dbName, tableName, phaseError(ctx, r_err, dbName+"."+tableName))
+	}
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func getPostgresBasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	var result metadataTable
+	result.dbName = dbName
+	result.tbName = tableName
+	result.fakePrimaryKey = false
+	result.onError = 0
+	result.isEmpty = true
+	result.withTrigger = false
+
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	p_err := adbConn.PingContext(ctx)
+	if p_err != nil {
+		return result, fmt.Errorf("can not ping\n%w", phaseError(ctx, p_err, dbName+"."+tableName))
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err := adbConn.QueryContext(ctx, "select coalesce(pg_total_relation_size(c.oid),-1),coalesce(c.reltuples::bigint,-1),'UNKNOW',TABLE_TYPE from information_schema.tables is_t left join pg_class c on c.relname = is_t.table_name left join pg_namespace n on n.oid = c.relnamespace and n.nspname = is_t.table_schema  WHERE table_schema = $1 AND table_name = $2     ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.tables for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	typeTable := "DO_NOT_EXIST"
+	for q_rows.Next() {
+		err := q_rows.Scan(&result.sizeBytes, &result.cntRows, &result.storageEng, &typeTable)
+		if err != nil {
+			return result, fmt.Errorf("can not query information_schema.tables for %s.%s\n%w", dbName, tableName, err)
+		}
+		if typeTable != "BASE TABLE" {
+			result.onError = result.onError | 8
+		}
+	}
+	if typeTable == "DO_NOT_EXIST" {
+		result.onError = result.onError | 16
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err = adbConn.QueryContext(ctx, "select COLUMN_NAME , DATA_TYPE,IS_NULLABLE,COALESCE(DATETIME_PRECISION,-9999),COALESCE(numeric_precision,-9999) FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = $1 AND table_name = $2 order by ORDINAL_POSITION ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.columns for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_col columnInfo
+		var a_str string
+		err := q_rows.Scan(&a_col.colName, &a_col.colType, &a_str, &a_col.dtPrec, &a_col.nuPrec)
+		if err != nil {
+			return result, fmt.Errorf("can not scan columns informations\n%w", err)
+		}
+		a_col.isNullable = (a_str == "YES")
+		a_col.isKindChar = (a_col.colType == "character varying")
+		a_col.isKindBinary = (a_col.colType == "bytea")
+		a_col.mustBeQuote = a_col.isKindChar || a_col.isKindBinary || (a_col.colType == "date" || a_col.colType == "timestamp without time zone" || a_col.colType == "time" || a_col.colType == "timestamp with time zone")
+		a_col.isKindFloat = (a_col.colType == "real" || a_col.colType == "double precision")
+		a_col.haveFract = (a_col.colType == "timestamp without time zone" || a_col.colType == "timestamp with time zone" || a_col.colType == "time") && (a_col.dtPrec > 0)
+		result.columnInfos = append(result.columnInfos, a_col)
+	}
+
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err = adbConn.QueryContext(ctx,
+		"SELECT pg_attribute.attname FROM pg_index, pg_class, pg_attribute, pg_namespace  WHERE nspname = $1 AND pg_class.relname = $2 AND indrelid = pg_class.oid AND pg_class.relnamespace = pg_namespace.oid AND    pg_attribute.attrelid = pg_class.oid AND pg_attribute.attnum = any(pg_index.indkey)  AND indisprimary order by array_position(pg_index.indkey,pg_attribute.attnum) ",
+		dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query INFORMATION_SCHEMA.STATISTICS  to get primary key info for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return result, fmt.Errorf("can not scan primary key informations\n%w", err)
+		}
+		result.primaryKey = append(result.primaryKey, a_str)
+	}
+
+	// ---------------------------------------------------------------------------------
+	result.fullName = fmt.Sprintf("\"%s\".\"%s\"", result.dbName, result.tbName)
+	result.cntCols = len(result.columnInfos)
+	// ---------------------------------------------------------------------------------
+	if result.onError == 0 {
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+
+		q_rows, q_err = adbConn.QueryContext(ctx, fmt.Sprintf("select 1 from %s limit 1", result.fullName))
+		if q_err != nil {
+			return result, fmt.Errorf("can not query the table %s for one row\n%w", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan a simple value from %s\n%w", result.fullName, err)
+			}
+			result.isEmpty = false
+		}
+		// -------------------------------------------------------------------------
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+
+		q_rows, q_err = adbConn.QueryContext(ctx, "select count(*) from INFORMATION_SCHEMA.TRIGGERS WHERE EVENT_OBJECT_SCHEMA = $1 AND EVENT_OBJECT_TABLE = $2", dbName, tableName)
+		if q_err != nil {
+			return result, fmt.Errorf("can not query INFORMATION_SCHEMA.TRIGGERS to detect trigger for table %s (%w)", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan count from INFORMATION_SCHEMA.TRIGGERS for table %s\n%w", result.fullName, err)
+			}
+			result.withTrigger = a_bigint > 0
+		}
+		// -------------------------------------------------------------------------
+	}
+	// ---------------------------------------------------------------------------------
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func getMsSqlBasicMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string) (metadataTable, error) {
+	var result metadataTable
+	result.dbName = dbName
+	result.tbName = tableName
+	result.fakePrimaryKey = false
+	result.onError = 0
+	result.isEmpty = true
+	result.withTrigger = false
+
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	p_err := adbConn.PingContext(ctx)
+	if p_err != nil {
+		return result, fmt.Errorf("can not ping\n%w", phaseError(ctx, p_err, dbName+"."+tableName))
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err := adbConn.QueryContext(ctx, "SELECT      SUM(a.total_pages) * 8 AS TotalSpaceKB, max(p.rows), 'UNKNOW',is_t.TABLE_TYPE "+
+		"            from information_schema.tables is_t "+
+		"            join sys.tables t on is_t.TABLE_NAME = t.name "+
+		"		INNER JOIN sys.indexes i ON t.object_id = i.object_id "+
+		"		INNER JOIN sys.partitions p ON i.object_id = p.object_id AND i.index_id = p.index_id "+
+		"		INNER JOIN sys.allocation_units a ON p.partition_id = a.container_id "+
+		"		LEFT OUTER JOIN sys.schemas s ON t.schema_id = s.schema_id and s.name=is_t.table_schema "+
+		"		WHERE "+
+		"		      is_t.table_schema= @p1 and is_t.table_name=@p2 "+
+		"		GROUP BY is_t.TABLE_TYPE , is_t.table_schema , is_t.table_name  ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.tables for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	typeTable := "DO_NOT_EXIST"
+	for q_rows.Next() {
+		err := q_rows.Scan(&result.sizeBytes, &result.cntRows, &result.storageEng, &typeTable)
+		if err != nil {
+			return result, fmt.Errorf("can not scan for query information_schema.tables for %s.%s\n%w", dbName, tableName, err)
+		}
+		if typeTable != "BASE TABLE" {
+			result.onError = result.onError | 8
+		}
+	}
+	if typeTable == "DO_NOT_EXIST" {
+		result.onError = result.onError | 16
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+
+	q_rows, q_err = adbConn.QueryContext(ctx, "select COLUMN_NAME , DATA_TYPE,IS_NULLABLE,COALESCE(DATETIME_PRECISION,-9999),COALESCE(numeric_precision,-9999) FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = @p1 AND table_name = @p2 order by ORDINAL_POSITION ", dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query information_schema.columns for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_col columnInfo
+		var a_str string
+		err := q_rows.Scan(&a_col.colName, &a_col.colType, &a_str, &a_col.dtPrec, &a_col.nuPrec)
+		if err != nil {
+			return result, fmt.Errorf("can not scan columns informations\n%w", err)
+		}
+		a_col.isNullable = (a_str == "YES")
+		a_col.isKindChar = (a_col.colType == "char" || a_col.colType == "varchar" || a_col.colType == "nchar" || a_col.colType == "nvarchar" || a_col.colType == "text" || a_col.colType == "ntext")
+		a_col.isKindBinary = (a_col.colType == "binary" || a_col.colType == "varbinary" || a_col.colType == "image")
+		a_col.mustBeQuote = a_col.isKindChar || a_col.isKindBinary || (a_col.colType == "date" || a_col.colType == "datetime" || a_col.colType == "datetime2" || a_col.colType == "smalldatetime" || a_col.colType == "datetimeoffset" || a_col.colType == "time")
+		a_col.isKindFloat = (a_col.colType == "real" || a_col.colType == "float")
+		a_col.haveFract = (a_col.colType == "datetime2" || a_col.colType == "datetimeoffset" || a_col.colType == "time") && (a_col.dtPrec > 0)
+		result.columnInfos = append(result.columnInfos, a_col)
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err = adbConn.QueryContext(ctx,
+		" SELECT column_name as PRIMARYKEYCOLUMN FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS TC INNER JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS KU ON TC.CONSTRAINT_TYPE = 'PRIMARY KEY'  AND TC.CONSTRAINT_NAME = KU.CONSTRAINT_NAME AND TC.TABLE_SCHEMA = KU.TABLE_SCHEMA AND KU.table_name=@P2 AND KU.TABLE_SCHEMA=@P1 order by KU.ORDINAL_POSITION ",
+		dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query INFORMATION_SCHEMA.TABLE_CONSTRAINTS to get primary key info for %s.%s\n%w", dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return result, fmt.Errorf("can not scan primary key informations\n%w", err)
+		}
+		result.primaryKey = append(result.primaryKey, a_str)
+	}
+
+	// ---------------------------------------------------------------------------------
+	result.fullName = fmt.Sprintf("[%s].[%s]", result.dbName, result.tbName)
+	result.cntCols = len(result.columnInfos)
+	// ---------------------------------------------------------------------------------
+	if result.onError == 0 {
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+
+		q_rows, q_err = adbConn.QueryContext(ctx, fmt.Sprintf("select top 1 1 from %s ", result.fullName))
+		if q_err != nil {
+			return result, fmt.Errorf("can not query the table %s for one row\n%w", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan a simple value from %s\n%w", result.fullName, err)
+			}
+			result.isEmpty = false
+		}
+		// -------------------------------------------------------------------------
+		ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+		defer cancel()
+		q_rows, q_err = adbConn.QueryContext(ctx, "SELECT count(*) FROM sys.triggers tr INNER JOIN sys.tables t INNER JOIn sys.schemas s ON t.schema_id = s.schema_id ON t.object_id = tr.parent_id WHERE  s.name = @P1 and t.name=@P2", dbName, tableName)
+		if q_err != nil {
+			return result, fmt.Errorf("can not query INFORMATION_SCHEMA.TRIGGERS to detect trigger for table %s (%w)", result.fullName, phaseError(ctx, q_err, dbName+"."+tableName))
+		}
+		for q_rows.Next() {
+			var a_bigint uint64
+			err := q_rows.Scan(&a_bigint)
+			if err != nil {
+				return result, fmt.Errorf("can not scan count from INFORMATION_SCHEMA.TRIGGERS for table %s\n%w", result.fullName, err)
+			}
+			result.withTrigger = a_bigint > 0
+		}
+		// -------------------------------------------------------------------------
+	}
+	// ---------------------------------------------------------------------------------
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// tables of dbNames matching one of tab2include ( all tables when empty ) and none of
+// tab2exclude , see tablePattern for the syntax of the patterns
+func getListTables(run_ctx context.Context, adbConn *sql.Conn, dbNames []string, tab2include []string, tab2exclude []string, srcdriver string) ([]aTable, error) {
+	var result []aTable
+	for _, v := range dbNames {
+		tables, l_err := getListTablesBySchema(run_ctx, adbConn, v, srcdriver)
+		if l_err != nil {
+			return nil, l_err
+		}
+		result = append(result, tables...)
+	}
+	return filterTables(result, tab2include, tab2exclude)
+}
+
+// ------------------------------------------------------------------------------------------
+func getListTablesBySchema(run_ctx context.Context, adbConn *sql.Conn, dbName string, srcdriver string) ([]aTable, error) {
+	var result []aTable
+	dialect := getSqlDialect(srcdriver)
+
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	p_err := adbConn.PingContext(ctx)
+	if p_err != nil {
+		return nil, fmt.Errorf("can not ping\n%w", phaseError(ctx, p_err, ""))
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, d_err := adbConn.QueryContext(ctx, dialect.SchemaExistsQuery(), dbName)
+	if d_err != nil {
+		return nil, fmt.Errorf("can not query database from  INFORMATION_SCHEMA.SCHEMATA for %s\n%w", dbName, phaseError(ctx, d_err, ""))
+	}
+	for q_rows.Next() {
+		var a_int int
+		err := q_rows.Scan(&a_int)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan database informations\n%w", err)
+		}
+		if a_int == 0 {
+			return nil, fmt.Errorf(" database '%s' does not exists", dbName)
+		}
+	}
+	ctx, cancel = phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, dialect.ListTablesQuery(), dbName)
+	if q_err != nil {
+		return nil, fmt.Errorf("can not list tables from  information_schema.tables for %s\n%w", dbName, phaseError(ctx, q_err, ""))
+	}
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan table informations\n%w", err)
+		}
+		result = append(result, aTable{dbName: dbName, tbName: a_str})
+	}
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+// every schema of the database , except the system schemas and the ones matching one of
+// sch2exclude , see schemaPattern for the syntax of the patterns
+func getListSchemas(run_ctx context.Context, adbConn *sql.Conn, sch2exclude []string, srcdriver string) ([]string, error) {
+	var result []string
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, getSqlDialect(srcdriver).ListSchemasQuery())
+	if q_err != nil {
+		return nil, fmt.Errorf("can not list schemas from INFORMATION_SCHEMA.SCHEMATA\n%w", phaseError(ctx, q_err, ""))
+	}
+	defer q_rows.Close()
+	for q_rows.Next() {
+		var a_str string
+		err := q_rows.Scan(&a_str)
+		if err != nil {
+			return nil, fmt.Errorf("can not scan schema informations\n%w", err)
+		}
+		result = append(result, a_str)
+	}
+	if r_err := q_rows.Err(); r_err != nil {
+		return nil, fmt.Errorf("can not list schemas from INFORMATION_SCHEMA.SCHEMATA\n%w", phaseError(ctx, r_err, ""))
+	}
+	result, f_err := filterSchemas(result, sch2exclude)
+	if f_err != nil {
+		return nil, f_err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no schema to dump")
+	}
+	return result, nil
+}
@@ -0,0 +1,247 @@
+// ------------------------------------------------------------------------------------------
+// Package paralib is the engine of paradump and parasync : sessions synchronized on one
+// snapshot , metadata of tables , and the browser / reader / generator / writer pipelines .
+//
+// a Dumper dumps tables into files or copies them into another database , a Syncer compares
+// tables of two databases and fixes the destination . both return the first failure from
+// Run and report what they do through a ProgressFunc .
+package paralib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+// the settings of one run of a Dumper or a Syncer , they go down with the contexts of the
+// run , so two runs in one process do not share them
+type runSettings struct {
+	debug    bool
+	trace    bool
+	timeouts Timeouts
+}
+
+type runSettingsKey struct{}
+
+// trace implies debug
+func withRunSettings(parent context.Context, debug bool, trace bool, timeouts Timeouts) context.Context {
+	return context.WithValue(parent, runSettingsKey{}, runSettings{debug: debug || trace, trace: trace, timeouts: timeouts})
+}
+
+func settingsOf(ctx context.Context) runSettings {
+	s, _ := ctx.Value(runSettingsKey{}).(runSettings)
+	return s
+}
+
+func isDebug(ctx context.Context) bool {
+	return settingsOf(ctx).debug
+}
+
+func isTrace(ctx context.Context) bool {
+	return settingsOf(ctx).trace
+}
+
+// the settings of parent without its cancellation , for what is given back to the servers
+// when the run stops
+func detachedContext(parent context.Context) context.Context {
+	return context.WithValue(context.Background(), runSettingsKey{}, settingsOf(parent))
+}
+
+// ------------------------------------------------------------------------------------------
+// only the goroutines of the pipelines fail with a panic of a fatalError , it becomes an
+// error again with fatalGroup.catch at the top of the goroutine . the other functions
+// return their errors . a panic that is not a fatalError is not ours and goes on
+type fatalError struct {
+	err error
+}
+
+func fail(err error) {
+	panic(fatalError{err: err})
+}
+
+func failf(format string, v ...any) {
+	panic(fatalError{err: fmt.Errorf(format, v...)})
+}
+
+// cleanup when the function returns an error
+func onFailure(ret_err *error, cleanup func()) {
+	if *ret_err != nil {
+		cleanup()
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// the first failure of a goroutine of the group is kept and cancels the context of the
+// group , the other goroutines see ctx.Done() and stop
+type fatalGroup struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+	err    error
+}
+
+func newFatalGroup(ctx context.Context) *fatalGroup {
+	g := &fatalGroup{parent: ctx}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	return g
+}
+
+func (g *fatalGroup) catch() {
+	if r := recover(); r != nil {
+		f_err, ok := r.(fatalError)
+		if !ok {
+			panic(r)
+		}
+		g.setErr(f_err.err)
+	}
+}
+
+// a goroutine of the group that returns its error instead of failing
+func (g *fatalGroup) setErr(err error) {
+	g.mutex.Lock()
+	if g.err == nil {
+		g.err = err
+	}
+	g.mutex.Unlock()
+	g.cancel()
+}
+
+// the first failure , else the error of the parent context
+func (g *fatalGroup) Err() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.err != nil {
+		return g.err
+	}
+	return g.parent.Err()
+}
+
+// receive from a goroutine of the group , returns the error of the group when it failed
+// instead of waiting forever for a goroutine that is gone
+func recvOrErr[T any](g *fatalGroup, ch chan T) (T, error) {
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-g.ctx.Done():
+		var zero T
+		return zero, g.Err()
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// Timeouts limit each statement of a phase , a zero field keeps the builtin timeout of the
+// statement ( from 1s to 60s , none for the chunk queries and the writes )
+//
+// Connect is for opening sessions and their settings , Metadata for catalog queries , Lock
+// for the locks and the start of the snapshot transactions , Browse for the queries of the
+// chunk boundaries , ChunkRead for reading a chunk ( the time spent waiting for the
+// generators is included ) , Write for the statements run on the destination
+type Timeouts struct {
+	Connect   time.Duration
+	Metadata  time.Duration
+	Lock      time.Duration
+	Browse    time.Duration
+	ChunkRead time.Duration
+	Write     time.Duration
+}
+
+const (
+	timeout_connect   = "connect"
+	timeout_metadata  = "metadata"
+	timeout_lock      = "lock"
+	timeout_browse    = "browse"
+	timeout_chunkread = "chunk read"
+	timeout_write     = "write"
+)
+
+type phaseTimeout struct {
+	phase   string
+	timeout time.Duration
+}
+
+type phaseTimeoutKey struct{}
+
+func (t Timeouts) get(phase string) time.Duration {
+	switch phase {
+	case timeout_connect:
+		return t.Connect
+	case timeout_metadata:
+		return t.Metadata
+	case timeout_lock:
+		return t.Lock
+	case timeout_browse:
+		return t.Browse
+	case timeout_chunkread:
+		return t.ChunkRead
+	case timeout_write:
+		return t.Write
+	}
+	return 0
+}
+
+// the context of one statement of a phase , builtin is used when no timeout is set for the
+// phase , zero is no timeout at all
+func phaseContext(parent context.Context, phase string, builtin time.Duration) (context.Context, context.CancelFunc) {
+	timeout := settingsOf(parent).timeouts.get(phase)
+	if timeout == 0 {
+		timeout = builtin
+	}
+	if timeout == 0 {
+		return context.WithCancel(parent)
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return context.WithValue(ctx, phaseTimeoutKey{}, phaseTimeout{phase: phase, timeout: timeout}), cancel
+}
+
+// when the statement failed because the timeout of its phase expired , the error says which
+// timeout and for which table ( empty when there is no table )
+func phaseError(ctx context.Context, err error, table string) error {
+	p_to, ok := ctx.Value(phaseTimeoutKey{}).(phaseTimeout)
+	if err == nil || !ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if len(table) == 0 {
+		return fmt.Errorf("%s timeout of %s expired\n%w", p_to.phase, p_to.timeout, err)
+	}
+	return fmt.Errorf("%s timeout of %s expired for table %s\n%w", p_to.phase, p_to.timeout, table, err)
+}
+
+// ------------------------------------------------------------------------------------------
+const (
+	Progress_Connected     = "connected"
+	Progress_Metadata      = "metadata"
+	Progress_ChunkBrowsed  = "chunk_browsed"
+	Progress_ChunkRead     = "chunk_read"
+	Progress_DstChunkRead  = "dst_chunk_read"
+	Progress_RowsWritten   = "rows_written"
+	Progress_RowsUnchanged = "rows_unchanged"
+	Progress_Status        = "status"
+	Progress_Done          = "done"
+)
+
+// ProgressEvent is what a Dumper or a Syncer reports while running
+//
+// Stage is one of the Progress_ constants , Table is schema.table ( empty for connected and
+// done ) , Rows is the estimated count of rows of the table for metadata , else the count
+// of rows of the chunk . Status is only set for status , reported at each ProgressInterval
+// of a Dumper
+type ProgressEvent struct {
+	Stage  string
+	Table  string
+	Rows   int64
+	Status *ProgressStatus
+}
+
+// ProgressFunc is called from several goroutines , it must be safe for concurrent use and
+// must not block
+type ProgressFunc func(ProgressEvent)
+
+func (f ProgressFunc) emit(ev ProgressEvent) {
+	if f != nil {
+		f(ev)
+	}
+}
@@ -0,0 +1,129 @@
+package paralib
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+// TableStatus is how far a table is , the estimates are the ones of the catalog ( times the
+// loop count ) . the bytes read are the bytes of the values , the bytes written are the bytes
+// of the statements or of the text before compression . ETA is -1 when nothing is written
+type TableStatus struct {
+	Table          string
+	EstimatedRows  int64
+	EstimatedBytes int64
+	RowsRead       int64
+	BytesRead      int64
+	RowsWritten    int64
+	BytesWritten   int64
+	ChunksInFlight int64
+	ETA            time.Duration
+}
+
+// ProgressStatus is a snapshot of a running Dumper , ChunksToRead , BlocksToGenerate and
+// BlocksToWrite are the depths of the queues between the stages , ChunksInFlight are the
+// chunks being read . ETA is from the rows written since the start of the pipeline , -1
+// when nothing is written
+type ProgressStatus struct {
+	Elapsed          time.Duration
+	Tables           []TableStatus
+	ChunksInFlight   int64
+	ChunksToRead     int
+	BlocksToGenerate int
+	BlocksToWrite    int
+	ETA              time.Duration
+}
+
+// ------------------------------------------------------------------------------------------
+// the counters of a table , updated by the readers and the writers . nil when the progress
+// is not reported
+type tableProgress struct {
+	rows_read      atomic.Int64
+	bytes_read     atomic.Int64
+	rows_written   atomic.Int64
+	bytes_written  atomic.Int64
+	chunks_reading atomic.Int64
+	first_read     atomic.Int64
+}
+
+func (p *tableProgress) startChunk() {
+	if p != nil {
+		p.first_read.CompareAndSwap(0, time.Now().UnixNano())
+		p.chunks_reading.Add(1)
+	}
+}
+
+func (p *tableProgress) endChunk(rows int, bytes int64) {
+	if p != nil {
+		p.rows_read.Add(int64(rows))
+		p.bytes_read.Add(bytes)
+		p.chunks_reading.Add(-1)
+	}
+}
+
+func (p *tableProgress) written(rows int, bytes int64) {
+	if p != nil {
+		p.rows_written.Add(int64(rows))
+		p.bytes_written.Add(bytes)
+	}
+}
+
+// the time left to write todo rows at the rate of done rows in elapsed
+func progressETA(elapsed time.Duration, done int64, todo int64) time.Duration {
+	if done == 0 {
+		return -1
+	}
+	if todo <= 0 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * float64(todo) / float64(done)).Round(time.Second)
+}
+
+// ------------------------------------------------------------------------------------------
+func progressStatus(start time.Time, tableInfos []metadataTable, loop_count int, queues func() (int, int, int)) *ProgressStatus {
+	now := time.Now()
+	ret_val := &ProgressStatus{Elapsed: now.Sub(start).Round(time.Second)}
+	ret_val.ChunksToRead, ret_val.BlocksToGenerate, ret_val.BlocksToWrite = queues()
+	var all_written, all_todo int64
+	for n := range tableInfos {
+		p := tableInfos[n].progress
+		a_tab := TableStatus{
+			Table:          tableInfos[n].fullName,
+			EstimatedRows:  tableInfos[n].cntRows * int64(loop_count),
+			EstimatedBytes: tableInfos[n].sizeBytes * int64(loop_count),
+			RowsRead:       p.rows_read.Load(),
+			BytesRead:      p.bytes_read.Load(),
+			RowsWritten:    p.rows_written.Load(),
+			BytesWritten:   p.bytes_written.Load(),
+			ChunksInFlight: p.chunks_reading.Load(),
+			ETA:            -1,
+		}
+		if first_read := p.first_read.Load(); first_read != 0 {
+			a_tab.ETA = progressETA(now.Sub(time.Unix(0, first_read)), a_tab.RowsWritten, a_tab.EstimatedRows-a_tab.RowsWritten)
+		}
+		all_written += a_tab.RowsWritten
+		if a_tab.EstimatedRows > a_tab.RowsWritten {
+			all_todo += a_tab.EstimatedRows - a_tab.RowsWritten
+		}
+		ret_val.ChunksInFlight += a_tab.ChunksInFlight
+		ret_val.Tables = append(ret_val.Tables, a_tab)
+	}
+	ret_val.ETA = progressETA(now.Sub(start), all_written, all_todo)
+	return ret_val
+}
+
+// ------------------------------------------------------------------------------------------
+// report a status at each interval until stop is closed
+func progressReporter(stop chan struct{}, interval time.Duration, start time.Time, tableInfos []metadataTable, loop_count int, queues func() (int, int, int), progress ProgressFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			progress.emit(ProgressEvent{Stage: Progress_Status, Status: progressStatus(start, tableInfos, loop_count, queues)})
+		}
+	}
+}
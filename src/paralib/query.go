@@ -0,0 +1,171 @@
+package paralib
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ------------------------------------------------------------------------------------------
+type colchunk struct {
+	kind int
+	val  *string
+}
+
+type rowchunk struct {
+	cols []sql.NullString
+}
+
+// ------------------------------------------------------------------------------------------
+func generateValuesForPredicat(indices []int, boundValues []string) []any {
+	sql_vals := make([]any, 0)
+	for _, v := range indices {
+		sql_vals = append(sql_vals, boundValues[v])
+	}
+	return sql_vals
+}
+
+// ------------------------------------------------------------------------------------------
+// lower bound is inclusive
+// upper bound is exclusive
+func generatePredicat(pkeyCols []string, lowerbound bool, enumCols []string, tablequote string, sql_placeholder string, sql_placeholder_start_pos int) (string, []int) {
+	var sql_pred string
+	sql_vals_indices := make([]int, 0)
+	ncolpkey := len(pkeyCols) - 1
+	op_1 := " <  "
+	op_o := " <  "
+	if lowerbound {
+		op_1 = " >=  "
+		op_o = " >   "
+	}
+	sql_pred = " ( "
+	for i := range pkeyCols {
+		col_is_enum := false
+		for e := range enumCols {
+			if enumCols[e] == pkeyCols[i] {
+				col_is_enum = true
+				break
+			}
+		}
+		place_holder := "?"
+		if len(sql_placeholder) > 2 {
+			place_holder = fmt.Sprintf(sql_placeholder, len(sql_vals_indices)+sql_placeholder_start_pos)
+		}
+		if col_is_enum {
+			place_holder = "cast(? as unsigned integer)"
+		}
+		if ncolpkey == i {
+			sql_pred = sql_pred + fmt.Sprintf(" ( %c%s%c %s %s ) ", tablequote[0], pkeyCols[i], tablequote[1], op_1, place_holder)
+		} else {
+			sql_pred = sql_pred + fmt.Sprintf(" ( %c%s%c %s %s ) ", tablequote[0], pkeyCols[i], tablequote[1], op_o, place_holder)
+		}
+		sql_vals_indices = append(sql_vals_indices, i)
+		if ncolpkey > 0 {
+			for j := 0; j < ncolpkey; j++ {
+				if j < i {
+					col_is_enum := false
+					for e := range enumCols {
+						if enumCols[e] == pkeyCols[j] {
+							col_is_enum = true
+							break
+						}
+					}
+					place_holder := "?"
+					if len(sql_placeholder) > 2 {
+						place_holder = fmt.Sprintf(sql_placeholder, len(sql_vals_indices)+sql_placeholder_start_pos)
+					}
+					if col_is_enum {
+						place_holder = "cast(? as unsigned integer)"
+					}
+
+					sql_pred = sql_pred + fmt.Sprintf(" and ( %c%s%c = %s ) ", tablequote[0], pkeyCols[j], tablequote[1], place_holder)
+					sql_vals_indices = append(sql_vals_indices, j)
+				}
+			}
+		}
+		if i < ncolpkey {
+			sql_pred = sql_pred + " ) or ( "
+		}
+	}
+	sql_pred = sql_pred + " ) "
+	return sql_pred, sql_vals_indices
+}
+
+// ------------------------------------------------------------------------------------------
+func generateEqualityPredicat(pkeyCols []string, enumCols []string, tablequote string, sql_placeholder string) (string, []int) {
+	var sql_pred string
+	sql_vals_indices := make([]int, 0)
+	sql_pred = " ( "
+	for i := range pkeyCols {
+		if i != 0 {
+			sql_pred = sql_pred + " and "
+		}
+		col_is_enum := false
+		for e := range enumCols {
+			if enumCols[e] == pkeyCols[i] {
+				col_is_enum = true
+				break
+			}
+		}
+		place_holder := "?"
+		if len(sql_placeholder) > 2 {
+			place_holder = fmt.Sprintf(sql_placeholder, 1+len(sql_vals_indices))
+		}
+		if col_is_enum {
+			place_holder = " cast( ? as unsigned integer ) "
+		}
+		sql_pred = sql_pred + fmt.Sprintf(" ( %c%s%c = %s ) ", tablequote[0], pkeyCols[i], tablequote[1], place_holder)
+		sql_vals_indices = append(sql_vals_indices, i)
+	}
+	sql_pred = sql_pred + " ) "
+	return sql_pred, sql_vals_indices
+}
+
+// ------------------------------------------------------------------------------------------
+func generateListPkCols4Sql(col_pk []string, dml_desc string, tablequote string) string {
+	if len(col_pk) == 0 {
+		return ""
+	}
+	a_str := quoteIdentifier(col_pk[0], tablequote) + " " + dml_desc
+	for ctab := 1; ctab < len(col_pk); ctab++ {
+		a_str = a_str + "," + quoteIdentifier(col_pk[ctab], tablequote) + " " + dml_desc
+	}
+	return a_str
+}
+
+// ------------------------------------------------------------------------------------------
+func generateListCols4Sql(col_inf []columnInfo, tablequote string) string {
+	if len(col_inf) == 0 {
+		return ""
+	}
+	a_str := quoteIdentifier(col_inf[0].colName, tablequote)
+	for ctab := 1; ctab < len(col_inf); ctab++ {
+		a_str = a_str + "," + quoteIdentifier(col_inf[ctab].colName, tablequote)
+	}
+	return a_str
+}
+
+// ------------------------------------------------------------------------------------------
+func generateListCols4Csv(col_inf []columnInfo) string {
+	if len(col_inf) == 0 {
+		return ""
+	}
+	a_str := col_inf[0].colName
+	for ctab := 1; ctab < len(col_inf); ctab++ {
+		a_str = a_str + "," + col_inf[ctab].colName
+	}
+	return a_str
+}
+
+// ------------------------------------------------------------------------------------------
+func isIntegerColumn(col_inf []columnInfo, colName string) bool {
+	for _, col := range col_inf {
+		if col.colName == colName {
+			switch col.colType {
+			case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
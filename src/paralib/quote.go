@@ -0,0 +1,403 @@
+package paralib
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ------------------------------------------------------------------------------------------
+//
+// replicate quoting string from  mysql
+//
+// https://github.com/mysql/mysql-server/blob/mysql-5.7.5/mysys/charset.c#L823-L932
+//
+// https://en.wikipedia.org/wiki/UTF-8#Encoding
+//
+// https://stackoverflow.com/questions/34861479/how-to-detect-when-bytes-cant-be-converted-to-string-in-go
+//
+//	example https://go.dev/play/p/6yHonH0Mae
+var quote_substitute_string_mysql = [256]uint8{
+	//    1    2    3    4     5    6    7    8    9    A    B     C    D    E    F
+	'0', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', 'n', ' ', ' ', 'r', ' ', ' ', // 0x00-0x0F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', 'Z', ' ', ' ', ' ', ' ', ' ', // 0x10-0x1F
+	' ', ' ', '"', ' ', ' ', ' ', ' ', '\'', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x20-0x2F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x30-0x3F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x40-0x4F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '\\', ' ', ' ', ' ', // 0x50-0x5F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x60-0x6F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x70-0x7F
+	//    1    2    3    4     5    6    7    8    9    A    B     C    D    E    F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x80-0x8F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x90-0x9F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xA0-0xAF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xB0-0xBF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xC0-0xCF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xD0-0xDF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xE0-0xEF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xF0-0xFF
+}
+
+func needCopyForquoteStringMysql(s_ptr *string) (*string, int, int, byte) {
+	s_len := len(*s_ptr)
+	if s_len == 0 {
+		return s_ptr, 0, -1, 0
+	}
+	b_pos := 0
+	var new_char byte
+	for {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_mysql[first_char]
+		if new_char != ' ' {
+			return s_ptr, s_len, b_pos, new_char
+		}
+		b_pos++
+		if b_pos == s_len {
+			return s_ptr, s_len, -1, new_char
+		}
+	}
+}
+
+func quoteStringFromPosMysql(s_ptr *string, s_len int, b_pos int, new_char byte) (*string, int) {
+	var new_str strings.Builder
+	new_str.WriteString((*s_ptr)[:b_pos])
+	new_str.WriteByte('\\')
+	new_str.WriteByte(new_char)
+	b_pos++
+	for b_pos < s_len {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_mysql[first_char]
+		if new_char == ' ' {
+			new_str.WriteByte(first_char)
+		} else {
+			new_str.WriteByte('\\')
+			new_str.WriteByte(new_char)
+		}
+		b_pos++
+	}
+	n_str := new_str.String()
+	return &n_str, len(n_str)
+}
+
+var quote_substitute_binary = [256]uint8{
+	//    1    2    3    4    5    6     7    8    9    A    B     C    D    E    F
+	'0', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', 'n', ' ', ' ', 'r', ' ', ' ', // 0x00-0x0F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', 'Z', ' ', ' ', ' ', ' ', ' ', // 0x10-0x1F
+	' ', ' ', '"', ' ', ' ', ' ', ' ', '\'', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x20-0x2F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x30-0x3F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x40-0x4F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '\\', ' ', ' ', ' ', // 0x50-0x5F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x60-0x6F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x70-0x7F
+	//    1    2    3    4    5    6     7    8    9    A    B     C    D    E    F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x80-0x8F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x90-0x9F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xA0-0xAF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xB0-0xBF
+	' ', ' ', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', // 0xC0-0xCF
+	'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', // 0xD0-0xDF
+	'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', // 0xE0-0xEF
+	'f', 'f', 'f', 'f', 'f', 'f', 'f', 'f', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xF0-0xFF
+}
+
+func quoteBinary(s_ptr *string) (*string, int) {
+	if s_ptr == nil || len(*s_ptr) == 0 {
+		return s_ptr, 0
+	}
+	b_pos := 0
+	need_a_copy := false
+	s_len := len(*s_ptr)
+	var new_char byte
+stringLoop:
+	for b_pos < s_len {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_binary[first_char]
+		switch new_char {
+		case ' ':
+			b_pos++
+		case 'f':
+			r, size := utf8.DecodeRuneInString((*s_ptr)[b_pos:])
+			if r == utf8.RuneError {
+				if first_char == 0xed && b_pos+2 < s_len && (*s_ptr)[b_pos+1] >= 0xa0 && (*s_ptr)[b_pos+1] <= 0xbf && (*s_ptr)[b_pos+2] >= 0x80 && (*s_ptr)[b_pos+2] <= 0xbf {
+					b_pos++
+					continue
+				}
+				need_a_copy = true
+				break stringLoop
+			}
+			b_pos += size
+		default:
+			need_a_copy = true
+			break stringLoop
+		}
+	}
+	if !need_a_copy {
+		return s_ptr, s_len
+	}
+	var new_str strings.Builder
+	new_str.Grow(s_len * 2)
+	new_str.WriteString((*s_ptr)[:b_pos])
+	for b_pos < s_len {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_binary[first_char]
+		switch new_char {
+		case ' ':
+			new_str.WriteByte(first_char)
+		case 'f':
+			r, size := utf8.DecodeRuneInString((*s_ptr)[b_pos:])
+			if r == utf8.RuneError {
+				// ED  a0  80   => ED  bf  bf
+				if first_char == 0xed && b_pos+2 < s_len && (*s_ptr)[b_pos+1] >= 0xa0 && (*s_ptr)[b_pos+1] <= 0xbf && (*s_ptr)[b_pos+2] >= 0x80 && (*s_ptr)[b_pos+2] <= 0xbf {
+					new_str.WriteByte(first_char)
+					new_str.WriteByte((*s_ptr)[b_pos+1])
+					new_str.WriteByte((*s_ptr)[b_pos+2])
+					b_pos += 3
+					continue
+				} else {
+					new_str.WriteByte('\\')
+					new_str.WriteByte(first_char)
+				}
+			} else {
+				new_str.WriteRune(r)
+				b_pos += size
+				continue
+			}
+		default:
+			new_str.WriteByte('\\')
+			new_str.WriteByte(new_char)
+		}
+		b_pos++
+	}
+	n_str := new_str.String()
+	return &n_str, len(n_str)
+}
+
+var quote_csv_string = [256]uint8{
+	//    1    2    3    4    5    6    7    8    9    A    B    C    D    E    F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', 'n', ' ', ' ', ' ', ' ', ' ', // 0x00-0x0F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x10-0x1F
+	' ', ' ', '"', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ',', ' ', ' ', ' ', // 0x20-0x2F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x30-0x3F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x40-0x4F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x50-0x5F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x60-0x6F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x70-0x7F
+	//    1    2    3    4     5    6    7    8    9    A    B   C    D    E    F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x80-0x8F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x90-0x9F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xA0-0xAF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xB0-0xBF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xC0-0xCF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xD0-0xDF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xE0-0xEF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xF0-0xFF
+}
+
+func needQuoteForCsv(s_ptr *string) bool {
+	s_len := len(*s_ptr)
+	if s_len == 0 {
+		return false
+	}
+	b_pos := 0
+	var new_char byte
+	for {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_csv_string[first_char]
+		if new_char == ' ' {
+			b_pos++
+			if b_pos == s_len {
+				return false
+			}
+		} else {
+			break
+		}
+	}
+	return true
+}
+
+// ------------------------------------------------------------------------------------------
+// postgres : we need only to quote the single quote
+var quote_substitute_string_postgres = [256]uint8{
+	//    1    2    3    4     5    6      7    8    9       A    B       C       D    E    F
+	'Z', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '\x0A', ' ', ' ', '\x0D', ' ', ' ', // 0x00-0x0F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '\x1A', ' ', ' ', ' ', ' ', ' ', // 0x10-0x1F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', '\x27', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x20-0x2F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x30-0x3F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x40-0x4F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', '\x5C', ' ', ' ', ' ', // 0x50-0x5F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x60-0x6F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x70-0x7F
+	//    1    2    3    4     5    6    7    8    9    A    B     C    D    E    F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x80-0x8F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0x90-0x9F
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xA0-0xAF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xB0-0xBF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xC0-0xCF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xD0-0xDF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xE0-0xEF
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', // 0xF0-0xFF
+}
+
+const hextable = "0123456789abcdef"
+
+func needCopyForquoteStringPostgres(s_ptr *string) (*string, int, int, byte) {
+	s_len := len(*s_ptr)
+	if s_len == 0 {
+		return s_ptr, 0, -1, 0
+	}
+	b_pos := 0
+	var new_char byte
+	for {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_postgres[first_char]
+		if new_char != ' ' {
+			return s_ptr, s_len, b_pos, new_char
+		}
+		b_pos++
+		if b_pos == s_len {
+			return s_ptr, s_len, -1, 0
+		}
+	}
+}
+
+func quoteStringFromPosPostgres(s_ptr *string, s_len int, b_pos int, new_char byte) (*string, int) {
+	var new_str strings.Builder
+	new_str.WriteString((*s_ptr)[:b_pos])
+	if new_char != 'Z' {
+		new_str.WriteString("\\x")
+		new_str.WriteByte(hextable[new_char>>4])
+		new_str.WriteByte(hextable[new_char&0x0f])
+	}
+	b_pos++
+	for b_pos < s_len {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_postgres[first_char]
+		if new_char == ' ' {
+			new_str.WriteByte(first_char)
+		} else if new_char != 'Z' {
+			new_str.WriteString("\\x")
+			new_str.WriteByte(hextable[new_char>>4])
+			new_str.WriteByte(hextable[new_char&0x0f])
+		}
+		b_pos++
+	}
+	n_str := new_str.String()
+	return &n_str, len(n_str)
+}
+
+// ------------------------------------------------------------------------------------------
+// mssql : we need only to quote the single quote
+var quote_substitute_string_mssql = [256]uint8{
+	//    1    2    3    4     5    6   7    8    9    A    B    C    D    E    F
+	'C', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'C', 'A', 'A', 'C', 'A', 'A', // 0x00-0x0F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'C', 'A', 'A', 'A', 'A', 'A', // 0x10-0x1F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'C', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x20-0x2F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x30-0x3F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x40-0x4F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x50-0x5F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x60-0x6F
+	'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', 'A', // 0x70-0x7F
+	//    1    2    3    4     5    6    7    8    9    A    B     C    D    E    F
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0x80-0x8F
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0x90-0x9F
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xA0-0xAF
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xB0-0xBF
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xC0-0xCF
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xD0-0xDF
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xE0-0xEF
+	'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', 'U', // 0xF0-0xFF
+}
+
+func stringIsASCII(s_ptr *string) bool {
+	s_len := len(*s_ptr)
+	if s_len == 0 {
+		return true
+	}
+	b_pos := 0
+	var new_char byte
+	for {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_mssql[first_char]
+		if new_char != 'A' {
+			return false
+		}
+		b_pos++
+		if b_pos == s_len {
+			return true
+		}
+	}
+}
+
+func needCopyForquoteStringMsSql(s_ptr *string) (*string, int, int, byte) {
+	s_len := len(*s_ptr)
+	if s_len == 0 {
+		return s_ptr, 0, -1, 0
+	}
+	b_pos := 0
+	var new_char byte
+	for {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_mssql[first_char]
+		if new_char != 'A' {
+			return s_ptr, s_len, b_pos, new_char
+		}
+		b_pos++
+		if b_pos == s_len {
+			return s_ptr, s_len, -1, 0
+		}
+	}
+}
+
+func quoteStringFromPosMsSql(s_ptr *string, s_len int, b_pos int, new_char byte) (*string, int) {
+	var new_str strings.Builder
+	need_plus := false
+	mode_n := false
+	new_str.WriteString((*s_ptr)[:b_pos])
+	if new_char == 'C' {
+		new_str.WriteString(fmt.Sprintf("'+CHAR(%d)", (*s_ptr)[b_pos]))
+		need_plus = true
+	}
+	if new_char == 'U' {
+		new_str.WriteString("'+N'")
+		new_str.WriteByte((*s_ptr)[b_pos])
+		need_plus = false
+		mode_n = true
+	}
+	b_pos++
+	for b_pos < s_len {
+		first_char := (*s_ptr)[b_pos]
+		new_char = quote_substitute_string_mssql[first_char]
+		if new_char == 'A' {
+			if need_plus {
+				new_str.WriteString("+'")
+				need_plus = false
+			}
+			new_str.WriteByte(first_char)
+		} else if new_char == 'C' {
+			if need_plus {
+				new_str.WriteString(fmt.Sprintf("+CHAR(%d)", new_char))
+			} else {
+				new_str.WriteString(fmt.Sprintf("'+CHAR(%d)", new_char))
+			}
+			need_plus = true
+			mode_n = false
+		} else if new_char == 'U' {
+			if need_plus {
+				new_str.WriteString("+N'")
+				need_plus = false
+			} else {
+				if !mode_n {
+					new_str.WriteString("'+N'")
+					mode_n = true
+				}
+				new_str.WriteByte(first_char)
+			}
+		}
+		b_pos++
+	}
+	if need_plus {
+		new_str.WriteString("+'")
+	}
+	n_str := new_str.String()
+	return &n_str, len(n_str)
+}
@@ -0,0 +1,2201 @@
+package paralib
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------
+const (
+	Action_Undefined = iota
+	Action_Read
+	Action_Write
+	Action_NoOp
+)
+
+const (
+	Phase_Undefined = iota
+	Phase_Browser
+	Phase_SrcReader
+	Phase_DstReader
+	Phase_DstWriter
+)
+
+type action2Stat struct {
+	Action int
+	Phase  int
+	TabId  int
+	Cnt    int
+}
+
+// -----------------------------------------------------------------------------------------
+func getSyncTableMetadataInfo(run_ctx context.Context, adbConn *sql.Conn, dbName string, tableName string, where string, columns columnFilter, guessPk bool, srcdriver string, dstdriver string, dstDbName string) (metadataTable, error) {
+	mode_debug := isDebug(run_ctx)
+	dialect := getSqlDialect(srcdriver)
+	result, m_err := dialect.BasicMetadataInfo(run_ctx, adbConn, dbName, tableName)
+	if m_err != nil {
+		return result, m_err
+	}
+	result.dstDbName = dstDbName
+	result.where = where
+	// ---------------------------------------------------------------------------------
+	enum_in_pk := (dstdriver != "mssql")
+	with_primary_index := false
+	//
+	enumPkCols := make([]string, 0)
+	if len(result.primaryKey) > 0 {
+		// -------------------------------------------------------------------------
+		for _, v := range result.columnInfos {
+			if v.colType == "enum" {
+				for _, pk := range result.primaryKey {
+					if pk == v.colName {
+						enumPkCols = append(enumPkCols, pk)
+					}
+				}
+			}
+		}
+		result.primaryKeyEnum = enumPkCols
+		// -------------------------------------------------------------------------
+		if len(enumPkCols) > 0 && (!enum_in_pk) {
+			result.primaryKey = nil
+			result.primaryKeyEnum = nil
+			enumPkCols = nil
+			with_primary_index = true
+		}
+	}
+	// ---------------------------------------------------------------------------------
+	ctx, cancel := phaseContext(run_ctx, timeout_metadata, 2*time.Second)
+	defer cancel()
+	q_rows, q_err := adbConn.QueryContext(ctx, dialect.IndexInfoQuery(with_primary_index), dbName, tableName)
+	if q_err != nil {
+		return result, fmt.Errorf("can not query %s catalog to get index infos for %s.%s\n%w", srcdriver, dbName, tableName, phaseError(ctx, q_err, dbName+"."+tableName))
+	}
+	var idx_cur indexInfo
+	for q_rows.Next() {
+		var a_cardina int64
+		var a_col_name string
+		var a_idx_name string
+
+		err := q_rows.Scan(&a_col_name, &a_cardina, &a_idx_name)
+		if err != nil {
+			return result, fmt.Errorf("can not scan indexes informations for %s.%s\n%w", dbName, tableName, err)
+		}
+		if len(idx_cur.idxName) != 0 && idx_cur.idxName != a_idx_name {
+			result.Indexes = append(result.Indexes, idx_cur)
+			idx_cur.columns = nil
+		}
+		if len(idx_cur.columns) > 0 {
+			result.Indexes = append(result.Indexes, idx_cur)
+		}
+		idx_cur.idxName = a_idx_name
+		idx_cur.cardinality = a_cardina
+		for _, v := range result.columnInfos {
+			if v.colName == a_col_name {
+				idx_cur.columns = append(idx_cur.columns, v)
+			}
+		}
+		if mode_debug {
+			log.Printf("idx_cur %v", idx_cur)
+		}
+	}
+	if len(idx_cur.idxName) != 0 {
+		result.Indexes = append(result.Indexes, idx_cur)
+	}
+
+	if len(result.primaryKey) == 0 {
+		if !guessPk {
+			result.onError = result.onError | 1
+		} else {
+			log.Printf("table %s.%s has no primary key\n", dbName, tableName)
+			if mode_debug {
+				log.Printf("table info is :")
+				log.Printf("t.dbName         : %s", result.dbName)
+				log.Printf("t.tbName         : %s", result.tbName)
+				log.Printf("t.cntRows        : %d", result.cntRows)
+				log.Printf("t.sizeBytes      : %d", result.sizeBytes)
+				log.Printf("t.storageEng     : %s", result.storageEng)
+				var a_str string
+				for _, v := range result.columnInfos {
+					if len(a_str) == 0 {
+						a_str = v.colName
+					} else {
+						a_str = a_str + " , " + v.colName
+					}
+				}
+				log.Printf("t.columnInfos    : [ %s ] ", a_str)
+				log.Printf("t.primaryKey     : %s", result.primaryKey)
+				log.Printf("t.Indexes        : ")
+				for _, i := range result.Indexes {
+					var a_str string
+					for _, v := range i.columns {
+						if len(a_str) == 0 {
+							a_str = v.colName
+						} else {
+							a_str = a_str + " , " + v.colName
+						}
+					}
+					log.Printf("         -  %9d [ %s ] %s ", i.cardinality, a_str, i.idxName)
+				}
+				log.Printf("t.fakePrimaryKey : %t", result.fakePrimaryKey)
+				log.Printf("t.onError        : %d", result.onError)
+			}
+			// -----------------------------------------------------------------
+			//
+			// result.Indexes contains a list of all indexes AND in case of multi-columns indexes ALL implicit indexes you can have
+			//
+			var max_cardinality int64
+			max_cardinality = -1
+			ix_pos := -1
+			for i, ix := range result.Indexes {
+				ix_have_null := false
+				for _, colinfo := range ix.columns {
+					ix_have_null = ix_have_null || colinfo.isNullable
+				}
+				ix_have_enum := false
+				for _, colinfo := range ix.columns {
+					ix_have_enum = ix_have_enum || colinfo.colType == "enum"
+				}
+				if !ix_have_null && ix.cardinality > max_cardinality && ((!ix_have_enum) || (ix_have_enum && enum_in_pk)) {
+					ix_pos = i
+					max_cardinality = ix.cardinality
+				}
+			}
+			// -----------------------------------------------------------------
+			if ix_pos == -1 {
+				result.onError = result.onError | 2
+			} else {
+				// ---------------------------------------------------------
+				if mode_debug {
+					log.Printf("we choose index %d", ix_pos)
+				}
+				result.fakePrimaryKey = true
+				for _, colinfo := range result.Indexes[ix_pos].columns {
+					result.primaryKey = append(result.primaryKey, colinfo.colName)
+				}
+				// ---------------------------------------------------------
+			}
+		}
+	}
+	result.cntPkCols = len(result.primaryKey)
+	// ---------------------------------------------------------------------------------
+	if mode_debug {
+		if len(enumPkCols) > 0 {
+			log.Printf(" for table %s we have enum in pk for column %s", result.fullName, enumPkCols)
+		}
+	}
+	// ---------------------------
+	// the pk is known , the columns that are not read can be removed
+	if !columns.isEmpty() {
+		result.columnInfos = columns.project(result.fullName, result.columnInfos, result.primaryKey)
+		result.cntCols = len(result.columnInfos)
+		result.projected = true
+	}
+	// ---------------------------
+	tablequote := dialect.IdentQuotes()
+	sql_cond_lower_pk, qry_indices_lo_bound := generatePredicat(result.primaryKey, true, enumPkCols, tablequote, dialect.PlaceholderFormat(), 1)
+	sql_cond_upper_pk, qry_indices_up_bound := generatePredicat(result.primaryKey, false, enumPkCols, tablequote, dialect.PlaceholderFormat(), 1+len(qry_indices_lo_bound))
+	sql_cond_bound_pk, _ := generatePredicat(result.primaryKey, false, enumPkCols, tablequote, dialect.PlaceholderFormat(), 1)
+	// ---------------------------
+	result.listColsSQL = generateListCols4Sql(result.columnInfos, tablequote)
+	result.listColsPkSQL = generateListPkCols4Sql(result.primaryKey, "", tablequote)
+	result.listColsPkFetchSQL = generateListPkColsFetch4Sync(result.primaryKey, enumPkCols, tablequote)
+	result.listColsPkOrderSQL = generateListPkCols4Sql(result.primaryKey, "", tablequote)
+	result.listColsPkOrderDescSQL = generateListPkCols4Sql(result.primaryKey, "desc", tablequote)
+	result.listColsCSV = generateListCols4Csv(result.columnInfos)
+	// ---------------------------
+	// the filter of the table is in every query , the chunks are still done on the pk
+	sql_filter := sqlTableWhere(result.where)
+	// the next query is a template for the limit , a % of the filter must stay a %
+	sql_next_filter := strings.ReplaceAll(sql_filter, "%", "%%")
+	sql_first_filter := ""
+	if len(result.where) > 0 {
+		sql_first_filter = "( " + result.where + " )"
+	}
+	// ---------------------------
+	// the limit of the next query is set by the browser , it stays as %d in the template
+	result.query_for_browser_first = dialect.SelectWithLimit(result.listColsPkFetchSQL, result.fullName, sql_first_filter, result.listColsPkOrderSQL, "1")
+	result.query_for_browser_next = dialect.FakePkCounterQuery(result.listColsPkFetchSQL, result.listColsPkSQL, result.fullName, sql_cond_lower_pk+sql_next_filter, result.listColsPkOrderSQL, result.listColsPkOrderDescSQL, "%d")
+	result.param_indices_browser_next_qry = qry_indices_lo_bound
+	// ---------------------------
+	result.query_for_reader_interval = fmt.Sprintf("/* parasync */ select %s from %s where ( %s ) and ( %s)%s ", result.listColsSQL, result.fullName, sql_cond_lower_pk, sql_cond_upper_pk, sql_filter)
+	result.param_indices_interval_lo_qry = qry_indices_lo_bound
+	result.param_indices_interval_up_qry = qry_indices_up_bound
+	// ---------------------------
+	result.query_for_reader_upper_bound = fmt.Sprintf("/* parasync */ select %s from %s where ( %s )%s           ", result.listColsSQL, result.fullName, sql_cond_bound_pk, sql_filter)
+	// ---------------------------
+	result.query_for_reader_lower_bound = fmt.Sprintf("/* parasync */ select %s from %s where ( %s )%s           ", result.listColsSQL, result.fullName, sql_cond_lower_pk, sql_filter)
+	// ---------------------------
+	// ---------------------------
+	if mode_debug {
+		if len(enumPkCols) > 0 {
+			log.Printf(" for table %s we need to apdat query because of enum in pk.\n%s", result.fullName, result.query_for_browser_first)
+			log.Printf(" for table %s we need to apdat query because of enum in pk.\n%s", result.fullName, result.query_for_browser_next)
+		}
+	}
+	// ---------------------------------------------------------------------------------
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func getSyncMetadataInfo4Tables(run_ctx context.Context, adbConn *sql.Conn, tableNames []aTable, guessPk bool, srcdriver string, dstdriver string) ([]metadataTable, error) {
+	var result []metadataTable
+	for j := 0; j < len(tableNames); j++ {
+		info, m_err := getSyncTableMetadataInfo(run_ctx, adbConn, tableNames[j].dbName, tableNames[j].tbName, tableNames[j].where, tableNames[j].columns, guessPk, srcdriver, dstdriver, tableNames[j].dstDbName)
+		if m_err != nil {
+			return nil, m_err
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].fullName < result[j].fullName })
+	log.Printf("-------------------")
+	cnt := 0
+	for i, v := range result {
+		if v.onError&1 == 1 && !(v.onError&16 == 16) {
+			log.Printf("table %s.%s has no primary key\n", v.dbName, v.tbName)
+			log.Print("you may want to use -guessprimarykey\n")
+			cnt++
+		}
+		if v.onError&2 == 2 {
+			log.Printf("table %s.%s has no alternative indexes to use as a primary key\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&4 == 4 {
+			log.Printf("table %s.%s is not a innodb table\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&8 == 8 {
+			log.Printf("table %s.%s is not a regular table\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if v.onError&16 == 16 {
+			log.Printf("table %s.%s does not exists\n", v.dbName, v.tbName)
+			cnt++
+		}
+		if i-1 >= 0 && v.onError&16 == 0 && v.fullName == result[i-1].fullName {
+			log.Printf("table %s.%s is referenced twice\n", v.dbName, v.tbName)
+			cnt++
+		}
+	}
+	if cnt > 0 {
+		return nil, fmt.Errorf("too many ERRORS")
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].sizeBytes > result[j].sizeBytes })
+	return result, nil
+}
+
+// ------------------------------------------------------------------------------------------
+func checkSyncTableOnDestination(run_ctx context.Context, driver string, adbConn *sql.Conn, a_table *metadataTable) (string, bool, error) {
+	mode_debug := isDebug(run_ctx)
+	var dstinfo metadataTable
+	var m_err error
+	if driver == "mysql" {
+		dstinfo, m_err = getMysqlBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+	} else {
+		if driver == "mssql" {
+			dstinfo, m_err = getMsSqlBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+		} else {
+			dstinfo, m_err = getPostgresBasicMetadataInfo(run_ctx, adbConn, a_table.dstDbName, a_table.tbName)
+		}
+	}
+	if m_err != nil {
+		return "", false, m_err
+	}
+	if a_table.projected {
+		dstinfo.columnInfos = projectColumns(a_table.columnInfos, dstinfo.columnInfos)
+	}
+	a_table.dstColumnInfos = dstinfo.columnInfos
+	if mode_debug {
+		log.Printf("tab %s.%s => %s.%s size cols src %2d dst %2d", a_table.dbName, a_table.tbName, a_table.dstDbName, a_table.tbName, len(a_table.columnInfos), len(a_table.dstColumnInfos))
+	}
+	res := reflect.DeepEqual(a_table.columnInfos, dstinfo.columnInfos)
+	err_msg := ""
+	if !res {
+		err_msg = fmt.Sprintf("columns definitions are not identical for %s and %s", a_table.fullName, dstinfo.fullName)
+		if mode_debug {
+			log.Printf("src:\n%v\n", a_table.columnInfos)
+			log.Printf("dst:\n%v\n", dstinfo.columnInfos)
+		}
+	}
+	if driver != "postgres" {
+		if dstinfo.withTrigger {
+			err_msg = err_msg + fmt.Sprintf(" / table %s have triggers", a_table.fullName)
+		}
+	} else {
+		if mode_debug {
+			log.Printf("Table %s on destination will be populate without firing triggers", a_table.fullName)
+		}
+	}
+	return err_msg, err_msg != "", nil
+}
+
+// ------------------------------------------------------------------------------------------
+func populateDstQueries(dstdriver string, adbConn *sql.Conn, a_table *metadataTable) {
+	// the filter is written for the source , it is also used as it is on the destination
+	sql_filter := sqlTableWhere(a_table.where)
+	if dstdriver == "postgres" {
+		dst_sql_cond_lower_pk, a := generatePredicat(a_table.primaryKey, true, nil, "  ", "$%d", 1)
+		dst_sql_cond_upper_pk, _ := generatePredicat(a_table.primaryKey, false, nil, "  ", "$%d", 1+len(a))
+		dst_sql_cond_bound_pk, _ := generatePredicat(a_table.primaryKey, false, nil, "  ", "$%d", 1)
+		dst_listsCols_Sql := generateListCols4Sql(a_table.columnInfos, "")
+		// -------------------
+		a_table.query_for_dst_reader_interval = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s ) and ( %s)%s ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, dst_sql_cond_upper_pk, sql_filter)
+		a_table.query_for_dst_reader_lower_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, sql_filter)
+		a_table.query_for_dst_reader_upper_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_bound_pk, sql_filter)
+		// -------------------
+		query_for_insert := fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES ( #", a_table.dstDbName, a_table.tbName, generateListCols4Sql(a_table.columnInfos, ""))
+		for range a_table.columnInfos[1:] {
+			query_for_insert = query_for_insert + " , # "
+		}
+		query_for_insert = query_for_insert + " ) "
+		a_table.query_for_insert = strings.Split(query_for_insert, "#")
+		query_for_update := fmt.Sprintf("UPDATE %s.%s set %s = # ", a_table.dstDbName, a_table.tbName, a_table.columnInfos[0].colName)
+		for _, v := range a_table.columnInfos[1:] {
+			query_for_update = query_for_update + fmt.Sprintf(", %s = # ", v.colName)
+		}
+		query_for_update = query_for_update + fmt.Sprintf(" where %s # ", a_table.columnInfos[0].colName)
+		for _, v := range a_table.columnInfos[1:] {
+			query_for_update = query_for_update + fmt.Sprintf("and %s # ", v.colName)
+		}
+		a_table.query_for_update = strings.Split(query_for_update, "#")
+		query_for_delete := fmt.Sprintf("DELETE FROM %s.%s ", a_table.dstDbName, a_table.tbName)
+		query_for_delete = query_for_delete + fmt.Sprintf(" where %s # ", a_table.columnInfos[0].colName)
+		for _, v := range a_table.columnInfos[1:] {
+			query_for_delete = query_for_delete + fmt.Sprintf("and %s # ", v.colName)
+		}
+		a_table.query_for_delete = strings.Split(query_for_delete, "#")
+	} else {
+		if dstdriver == "mssql" {
+			dst_sql_cond_lower_pk, a := generatePredicat(a_table.primaryKey, true, nil, "  ", "@p%d", 1)
+			dst_sql_cond_upper_pk, _ := generatePredicat(a_table.primaryKey, false, nil, "  ", "@p%d", 1+len(a))
+			dst_sql_cond_bound_pk, _ := generatePredicat(a_table.primaryKey, false, nil, "  ", "@p%d", 1)
+			dst_listsCols_Sql := generateListCols4Sql(a_table.columnInfos, "")
+			// -------------------
+			a_table.query_for_dst_reader_interval = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s ) and ( %s)%s ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, dst_sql_cond_upper_pk, sql_filter)
+			a_table.query_for_dst_reader_lower_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, sql_filter)
+			a_table.query_for_dst_reader_upper_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_bound_pk, sql_filter)
+			// -------------------
+			query_for_insert := fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES ( #", a_table.dstDbName, a_table.tbName, generateListCols4Sql(a_table.columnInfos, ""))
+			for range a_table.columnInfos[1:] {
+				query_for_insert = query_for_insert + " , # "
+			}
+			query_for_insert = query_for_insert + ")"
+			a_table.query_for_insert = strings.Split(query_for_insert, "#")
+			query_for_update := fmt.Sprintf("UPDATE %s.%s set %s = # ", a_table.dstDbName, a_table.tbName, a_table.columnInfos[0].colName)
+			for _, v := range a_table.columnInfos[1:] {
+				query_for_update = query_for_update + fmt.Sprintf(", %s = # ", v.colName)
+			}
+			if a_table.dstColumnInfos[0].colType == "text" {
+				query_for_update = query_for_update + fmt.Sprintf(" where cast( %s as nvarchar(MAX)) # ", a_table.columnInfos[0].colName)
+			} else {
+				query_for_update = query_for_update + fmt.Sprintf(" where %s # ", a_table.columnInfos[0].colName)
+			}
+			for i, v := range a_table.columnInfos[1:] {
+				if a_table.dstColumnInfos[i+1].colType == "text" {
+					query_for_update = query_for_update + fmt.Sprintf("and cast( %s as nvarchar(MAX)) # ", v.colName)
+				} else {
+					query_for_update = query_for_update + fmt.Sprintf("and %s # ", v.colName)
+				}
+			}
+			a_table.query_for_update = strings.Split(query_for_update, "#")
+			query_for_delete := fmt.Sprintf("DELETE FROM %s.%s ", a_table.dstDbName, a_table.tbName)
+			query_for_delete = query_for_delete + fmt.Sprintf(" where %s # ", a_table.columnInfos[0].colName)
+			for _, v := range a_table.columnInfos[1:] {
+				query_for_delete = query_for_delete + fmt.Sprintf("and %s # ", v.colName)
+			}
+			a_table.query_for_delete = strings.Split(query_for_delete, "#")
+		} else {
+			dst_sql_cond_lower_pk, a := generatePredicat(a_table.primaryKey, true, a_table.primaryKeyEnum, "``", "?", 1)
+			dst_sql_cond_upper_pk, _ := generatePredicat(a_table.primaryKey, false, a_table.primaryKeyEnum, "``", "?", 1+len(a))
+			dst_listsCols_Sql := generateListCols4Sql(a_table.columnInfos, "``")
+			// -------------------
+			a_table.query_for_dst_reader_interval = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s ) and ( %s)%s ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, dst_sql_cond_upper_pk, sql_filter)
+			a_table.query_for_dst_reader_lower_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_lower_pk, sql_filter)
+			a_table.query_for_dst_reader_upper_bound = fmt.Sprintf("/* parasync */ select %s from %s.%s where ( %s )%s           ", dst_listsCols_Sql, a_table.dstDbName, a_table.tbName, dst_sql_cond_upper_pk, sql_filter)
+			// -------------------
+			query_for_insert := fmt.Sprintf("INSERT INTO %s.%s(%s) VALUES ( #", a_table.dstDbName, a_table.tbName, dst_listsCols_Sql)
+			for range a_table.columnInfos[1:] {
+				query_for_insert = query_for_insert + " , # "
+			}
+			query_for_insert = query_for_insert + ")"
+			a_table.query_for_insert = strings.Split(query_for_insert, "#")
+			query_for_update := fmt.Sprintf("UPDATE %s.%s set %s = # ", a_table.dstDbName, a_table.tbName, a_table.columnInfos[0].colName)
+			for _, v := range a_table.columnInfos[1:] {
+				query_for_update = query_for_update + fmt.Sprintf(", %s = # ", v.colName)
+			}
+			query_for_update = query_for_update + fmt.Sprintf(" where %s # ", a_table.columnInfos[0].colName)
+			for _, v := range a_table.columnInfos[1:] {
+				query_for_update = query_for_update + fmt.Sprintf("and %s # ", v.colName)
+			}
+			a_table.query_for_update = strings.Split(query_for_update, "#")
+			query_for_delete := fmt.Sprintf("DELETE FROM %s.%s ", a_table.dstDbName, a_table.tbName)
+			query_for_delete = query_for_delete + fmt.Sprintf(" WHERE %s # ", a_table.columnInfos[0].colName)
+			for _, v := range a_table.columnInfos[1:] {
+				query_for_delete = query_for_delete + fmt.Sprintf("and %s # ", v.colName)
+			}
+			a_table.query_for_delete = strings.Split(query_for_delete, "#")
+		}
+	}
+	for _, s := range a_table.query_for_insert {
+		a_table.query_for_insert_size = a_table.query_for_insert_size + len(s)
+	}
+	for _, s := range a_table.query_for_update {
+		a_table.query_for_update_size = a_table.query_for_update_size + len(s)
+	}
+	for _, s := range a_table.query_for_delete {
+		a_table.query_for_delete_size = a_table.query_for_delete_size + len(s)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+//
+// will fetch DDL informations on destination database , and compare table definitions
+func checkSyncTablesOnDestination(run_ctx context.Context, srcdriver string, dstdriver string, adbConn *sql.Conn, infTables *[]metadataTable) error {
+	mode_debug := isDebug(run_ctx)
+	cnterr := 0
+	for n := range *infTables {
+		msg, err, c_err := checkSyncTableOnDestination(run_ctx, dstdriver, adbConn, &(*infTables)[n])
+		if c_err != nil {
+			return c_err
+		}
+		if err {
+			log.Printf("issue with table %s.%s on destination", (*infTables)[n].dbName, (*infTables)[n].tbName)
+			log.Printf("%s", msg)
+			cnterr++
+		}
+		if mode_debug {
+			log.Printf("tab %s.%s => %s.%s size cols src %2d dst %2d", (*infTables)[n].dbName, (*infTables)[n].tbName, (*infTables)[n].dstDbName, (*infTables)[n].tbName, len((*infTables)[n].columnInfos), len((*infTables)[n].dstColumnInfos))
+		}
+		populateDstQueries(dstdriver, adbConn, &(*infTables)[n])
+	}
+	if srcdriver != dstdriver {
+		log.Printf("WARNING source is on %s and destination is on %s , i will assume that will work.", srcdriver, dstdriver)
+	} else {
+		if cnterr > 0 {
+			return fmt.Errorf("too many ERRORS")
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------
+type synctablechunk struct {
+	table_id        int
+	chunk_id        int64
+	begin_val_mysql []string
+	end_val_mysql   []string
+	begin_val_oth   []string
+	end_val_oth     []string
+}
+
+type syncdatachunk struct {
+	tchunk *synctablechunk
+	rows   []*rowchunk
+}
+
+type datachunkpair struct {
+	c_src *syncdatachunk
+	c_dst *syncdatachunk
+}
+
+// ------------------------------------------------------------------------------------------
+const (
+	DML_Undefined = iota
+	DML_Insert
+	DML_Update
+	DML_Delete
+)
+
+// ------------------------------------------------------------------------------------------
+type datarow struct {
+	table_id int
+	dmltype  int
+	valdta   *rowchunk
+	whrdta   *rowchunk
+}
+
+// ------------------------------------------------------------------------------------------
+type syncinsertchunk struct {
+	table_id int
+	dmltype  int
+	cntrows  int
+	sql      *string
+	params   *[]any
+}
+
+// ------------------------------------------------------------------------------------------
+func pk_extract_values(a_pk_row []string, a_table_inf metadataTable, enum_cast bool) []string {
+	res_val := make([]string, a_table_inf.cntPkCols)
+	j := 0
+	for i := range a_table_inf.primaryKey {
+		col_is_enum := false
+		for e := range a_table_inf.primaryKeyEnum {
+			if a_table_inf.primaryKeyEnum[e] == a_table_inf.primaryKey[i] {
+				col_is_enum = true
+				break
+			}
+		}
+		if col_is_enum {
+			if enum_cast {
+				res_val[i] = a_pk_row[j+1]
+			} else {
+				res_val[i] = a_pk_row[j]
+			}
+			j++
+		} else {
+			res_val[i] = a_pk_row[j]
+		}
+		j++
+	}
+	return res_val
+}
+
+// ------------------------------------------------------------------------------------------
+func syncTableChunkBrowser(ctx_root context.Context, adbConn *sql.Conn, id int, tableidstoscan chan int, tableInfos []metadataTable, chunk2read chan synctablechunk, sizeofchunk_init int, stats2push chan action2Stat) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableChunkBrowser  [%02d]: start\n", id)
+	}
+	var sizeofchunk int
+	var must_prepare_query bool
+
+	ctx, cancel := phaseContext(ctx_root, timeout_connect, 2*time.Second)
+	p_err := adbConn.PingContext(ctx)
+	cancel()
+	if p_err != nil {
+		if ctx_root.Err() != nil {
+			return
+		}
+		failf("can not ping\n%w", phaseError(ctx, p_err, ""))
+	}
+	format_cnt_table := " %0" + fmt.Sprintf("%d", len(fmt.Sprintf("%d", len(tableInfos)))) + "d "
+	for {
+		var j int
+		select {
+		case <-ctx_root.Done():
+			j = -1
+		case j = <-tableidstoscan:
+		}
+		if j == -1 {
+			break
+		}
+		sizeofchunk = sizeofchunk_init
+		resultset_pk_size := tableInfos[j].cntPkCols + len(tableInfos[j].primaryKeyEnum)
+		if mode_debug {
+			log.Printf("tableChunkBrowser  [%02d]: table %s size pk %d query :  %s \n", id, tableInfos[j].fullName, tableInfos[j].cntPkCols, tableInfos[j].query_for_browser_first)
+		}
+		ctx, cancel = phaseContext(ctx_root, timeout_browse, 16*time.Second)
+		q_rows, q_err := adbConn.QueryContext(ctx, tableInfos[j].query_for_browser_first)
+		if q_err != nil {
+			cancel()
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not query table %s to get first pk aka ( %s )\n%w", tableInfos[j].fullName, tableInfos[j].listColsPkSQL, phaseError(ctx, q_err, tableInfos[j].fullName))
+		}
+		a_sql_row := make([]*sql.NullString, resultset_pk_size)
+		var pk_cnt int
+		ptrs := make([]any, resultset_pk_size)
+		var ptrs_nextpk []any
+		ptrs_nextpk = make([]any, resultset_pk_size+1)
+		for i := range a_sql_row {
+			ptrs[i] = &a_sql_row[i]
+			ptrs_nextpk[i] = &a_sql_row[i]
+		}
+		ptrs_nextpk[resultset_pk_size] = &pk_cnt
+		row_cnt := 0
+		for q_rows.Next() {
+			err := q_rows.Scan(ptrs...)
+			if err != nil {
+				failf("can not scan result for table %s\n%w", tableInfos[j].fullName, err)
+			}
+			row_cnt++
+		}
+		r_err := q_rows.Err()
+		cancel()
+		if r_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not read first pk of table %s\n%w", tableInfos[j].fullName, phaseError(ctx, r_err, tableInfos[j].fullName))
+		}
+		if row_cnt == 0 {
+			log.Printf("table["+format_cnt_table+"] %s is empty \n", j, tableInfos[j].fullName)
+			continue
+		}
+		start_pk_row := make([]string, resultset_pk_size)
+		for n, value := range a_sql_row {
+			start_pk_row[n] = value.String
+		}
+		log.Printf("tableChunkBrowser  [%02d]: table["+format_cnt_table+"] %s first pk ( %s ) - start scan pk %s\n", id, j, tableInfos[j].fullName, tableInfos[j].listColsPkSQL, pk_extract_values(start_pk_row, tableInfos[j], true))
+		// --------------------------------------------------------------------------
+		var the_finish_query string
+		var prepare_finish_query *sql.Stmt
+		var p_err error
+		var end_pk_row []string
+		var chunk_id int64 = 100000000 * (int64(j) + 1)
+		// --------------------------------------------------------------------------
+		end_pk_row = start_pk_row
+		sizeofchunk = math.MaxInt
+		// --------------------------------------------------------------------------
+		chunk_id++
+		select {
+		case <-ctx_root.Done():
+			return
+		case chunk2read <- synctablechunk{table_id: j, chunk_id: chunk_id, end_val_mysql: pk_extract_values(end_pk_row, tableInfos[j], true), end_val_oth: pk_extract_values(end_pk_row, tableInfos[j], false)}:
+		}
+		// --------------------------------------------------------------------------
+		for {
+			start_pk_row = end_pk_row
+			begin_equal_end := false
+			if sizeofchunk > sizeofchunk_init {
+				must_prepare_query = true
+				sizeofchunk = sizeofchunk_init
+			}
+			for {
+				// ----------------------------------------------------------
+				if must_prepare_query {
+					the_finish_query = fmt.Sprintf(tableInfos[j].query_for_browser_next, sizeofchunk)
+					if prepare_finish_query != nil {
+						_ = prepare_finish_query.Close()
+					}
+					ctx, cancel = phaseContext(ctx_root, timeout_browse, 2*time.Second)
+					prepare_finish_query, p_err = adbConn.PrepareContext(ctx, the_finish_query)
+					cancel()
+					if p_err != nil {
+						if ctx_root.Err() != nil {
+							return
+						}
+						failf("can not prepare to get next pk ( %s )\n%w", the_finish_query, phaseError(ctx, p_err, tableInfos[j].fullName))
+					}
+					must_prepare_query = false
+				}
+				// ----------------------------------------------------------
+				sql_vals_pk := generateValuesForPredicat(tableInfos[j].param_indices_browser_next_qry, pk_extract_values(start_pk_row, tableInfos[j], true))
+				ctx, cancel = phaseContext(ctx_root, timeout_browse, 0)
+				q_rows, q_err = prepare_finish_query.QueryContext(ctx, sql_vals_pk...)
+				if q_err != nil {
+					cancel()
+					if ctx_root.Err() != nil {
+						return
+					}
+					failf("can not query table %s to get next pk aka ( %s )\n%w", tableInfos[j].fullName, tableInfos[j].listColsPkSQL, phaseError(ctx, q_err, tableInfos[j].fullName))
+				}
+				for q_rows.Next() {
+					err := q_rows.Scan(ptrs_nextpk...)
+					if err != nil {
+						log.Printf("can not scan result for table %s doing get next pk ( len ptrs_nextpk %d ) ( len sql_vals_pk %d) \n", tableInfos[j].fullName, len(ptrs_nextpk), len(sql_vals_pk))
+						log.Printf("query for nextpk is %s", the_finish_query)
+						fail(err)
+					}
+				}
+				r_err = q_rows.Err()
+				cancel()
+				if r_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					failf("can not read next pk of table %s\n%w", tableInfos[j].fullName, phaseError(ctx, r_err, tableInfos[j].fullName))
+				}
+				end_pk_row = make([]string, resultset_pk_size)
+				for n := range a_sql_row {
+					end_pk_row[n] = a_sql_row[n].String
+				}
+				if mode_debug {
+					log.Printf("tableChunkBrowser  [%02d]: table %s query :  %s \n with %d params\nval for query: %s\nresult: %s\n", id, tableInfos[j].fullName, the_finish_query, len(sql_vals_pk), sql_vals_pk, end_pk_row)
+					log.Printf("tableChunkBrowser  [%02d]: table %s pk interval : %s -> %s\n", id, tableInfos[j].fullName, start_pk_row, end_pk_row)
+					log.Printf("tableChunkBrowser  [%02d]: sizeofchunk %6d pk_cnt %6d start equal end %t\n", id, sizeofchunk, pk_cnt, reflect.DeepEqual(start_pk_row, end_pk_row))
+				}
+				begin_equal_end = reflect.DeepEqual(start_pk_row, end_pk_row)
+				if tableInfos[j].fakePrimaryKey && begin_equal_end && pk_cnt >= sizeofchunk {
+					sizeofchunk = sizeofchunk * 15 / 10
+					must_prepare_query = true
+				} else {
+					break
+				}
+				// ----------------------------------------------------------
+			}
+			// ------------------------------------------------------------------
+			chunk_id++
+			var a_chunk synctablechunk
+			a_chunk.table_id = j
+			a_chunk.chunk_id = chunk_id
+			a_chunk.begin_val_mysql = pk_extract_values(start_pk_row, tableInfos[j], true)
+			a_chunk.begin_val_oth = pk_extract_values(start_pk_row, tableInfos[j], false)
+			if !begin_equal_end {
+				a_chunk.end_val_mysql = pk_extract_values(end_pk_row, tableInfos[j], true)
+				a_chunk.end_val_oth = pk_extract_values(end_pk_row, tableInfos[j], false)
+				stats2push <- action2Stat{Action: Action_Read, Phase: Phase_Browser, TabId: j, Cnt: pk_cnt - 1}
+			} else {
+				stats2push <- action2Stat{Action: Action_Read, Phase: Phase_Browser, TabId: j, Cnt: pk_cnt}
+			}
+			select {
+			case <-ctx_root.Done():
+				return
+			case chunk2read <- a_chunk:
+			}
+			// ------------------------------------------------------------------
+			if begin_equal_end {
+				break
+			}
+		}
+		log.Printf("table["+format_cnt_table+"] %s scan is done , pk col ( %s ) scan size pk %d last pk %s\n", j, tableInfos[j].fullName, tableInfos[j].listColsPkSQL, pk_cnt, end_pk_row)
+		// --------------------------------------------------------------------------
+		if prepare_finish_query != nil {
+			_ = prepare_finish_query.Close()
+		}
+		// --------------------------------------------------------------------------
+	}
+	if mode_debug {
+		log.Printf("tableChunkBrowser [%02d]: finish\b", id)
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func chunkReaderProcess(run_ctx context.Context, threadid int, q_rows *sql.Rows, a_table_info *metadataTable, tch *synctablechunk) *syncdatachunk {
+	mode_debug := isDebug(run_ctx)
+	// --------------------------------------------------------------------------
+	var a_dta_chunk syncdatachunk
+	a_dta_chunk = syncdatachunk{tchunk: tch, rows: make([]*rowchunk, 0)}
+	if mode_debug {
+		log.Printf("chunkReaderProcess [%02d]: table %03d chunk %12d \n", threadid, tch.table_id, tch.chunk_id)
+	}
+	ptrs := make([]any, a_table_info.cntCols)
+	for q_rows.Next() {
+		var a_simple_row rowchunk
+		a_simple_row.cols = make([]sql.NullString, a_table_info.cntCols)
+		// ------------------------------------------------------------------
+		for i := 0; i < a_table_info.cntCols; i++ {
+			ptrs[i] = &a_simple_row.cols[i]
+		}
+		// ------------------------------------------------------------------
+		err := q_rows.Scan(ptrs...)
+		if err != nil {
+			log.Printf("can not scan %s , len(ptrs) = %d ", a_table_info.tbName, len(ptrs))
+			fail(err)
+		}
+		// ------------------------------------------------------------------
+		a_dta_chunk.rows = append(a_dta_chunk.rows, &a_simple_row)
+		// ------------------------------------------------------------------
+	}
+	if mode_debug {
+		log.Printf("chunkReaderProcess [%02d]: table %03d chunk %12d len %5d\n", threadid, tch.table_id, tch.chunk_id, len(a_dta_chunk.rows))
+	}
+	return &a_dta_chunk
+}
+
+// ------------------------------------------------------------------------------------------
+type cacheSyncTableChunkReader struct {
+	table_id                  int
+	lastusagecnt              int
+	fullname                  string
+	indices_lo_pk             []int
+	indices_up_pk             []int
+	interval_query            string
+	lower_bound_query         string
+	upper_bound_query         string
+	interval_prepared_stmt    *sql.Stmt
+	lower_bound_prepared_stmt *sql.Stmt
+	upper_bound_prepared_stmt *sql.Stmt
+}
+
+// ------------------------------------------------------------------------------------------
+func syncTableChunkReader(ctx_root context.Context, chunk2read chan synctablechunk, chan2dstreader chan syncdatachunk, adbConn *sql.Conn, tableInfos []metadataTable, id int, cntBrowser int, stats2push chan action2Stat) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableChunkReader   [%02d]: start\n", id)
+	}
+	var cntreadchunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabReadingVars []*cacheSyncTableChunkReader
+
+	var last_table *cacheSyncTableChunkReader
+
+	tabReadingVars = make([]*cacheSyncTableChunkReader, cntBrowser+1)
+
+	for {
+		var a_chunk synctablechunk
+		select {
+		case <-ctx_root.Done():
+			a_chunk.table_id = -1
+		case a_chunk = <-chunk2read:
+		}
+		if a_chunk.table_id == -1 {
+			break
+		}
+		cntreadchunk++
+
+		if last_table == nil || last_table.table_id != a_chunk.table_id {
+			// ------------------------------------------------------------------
+			var tab_found int = -1
+			var empty_slot int = -1
+			var lru_slot int = -1
+			var lru_val int = math.MaxInt
+			for n := range tabReadingVars {
+				if tabReadingVars[n] == nil {
+					empty_slot = n
+				} else if tabReadingVars[n].table_id == a_chunk.table_id {
+					tab_found = n
+					break
+				} else {
+					if tabReadingVars[n].lastusagecnt < lru_val {
+						lru_val = tabReadingVars[n].lastusagecnt
+						lru_slot = n
+					}
+				}
+			}
+			if tab_found == -1 {
+				cache_miss++
+				// ----------------------------------------------------------
+				if empty_slot == -1 {
+					tabReadingVars[lru_slot].interval_prepared_stmt.Close()
+					tabReadingVars[lru_slot].lower_bound_prepared_stmt.Close()
+					tabReadingVars[lru_slot].upper_bound_prepared_stmt.Close()
+					tabReadingVars[lru_slot] = nil
+					empty_slot = lru_slot
+				}
+				// ----------------------------------------------------------
+				var new_info cacheSyncTableChunkReader
+
+				new_info.table_id = a_chunk.table_id
+				new_info.lastusagecnt = cntreadchunk
+
+				new_info.fullname = tableInfos[a_chunk.table_id].fullName
+				new_info.interval_query = tableInfos[a_chunk.table_id].query_for_reader_interval
+				new_info.lower_bound_query = tableInfos[a_chunk.table_id].query_for_reader_lower_bound
+				new_info.upper_bound_query = tableInfos[a_chunk.table_id].query_for_reader_upper_bound
+				new_info.indices_lo_pk = tableInfos[a_chunk.table_id].param_indices_interval_lo_qry
+				new_info.indices_up_pk = tableInfos[a_chunk.table_id].param_indices_interval_up_qry
+				// ------------------------------------------------------------------
+				ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				var p_err error
+				new_info.interval_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.interval_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableChunkReader   [%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a chunk ( %s )\n%w", new_info.interval_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				ctx, cancel = phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				new_info.lower_bound_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.lower_bound_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableChunkReader   [%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a lower bounded chunk ( %s )\n%w", new_info.lower_bound_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				ctx, cancel = phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				new_info.upper_bound_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.upper_bound_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableChunkReader   [%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a upper bounded chunk ( %s )\n%w", new_info.upper_bound_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				// ------------------------------------------------------------------
+				tabReadingVars[empty_slot] = &new_info
+				last_table = &new_info
+			} else {
+				cache_hit++
+				last_table = tabReadingVars[tab_found]
+				last_table.lastusagecnt = cntreadchunk
+			}
+		} else {
+			last_hit++
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("tableChunkReader   [%02d]: table %03d chunk %12d %s -> %s\n", id, a_chunk.table_id, a_chunk.chunk_id, a_chunk.begin_val_oth, a_chunk.end_val_oth)
+		}
+		// --------------------------------------------------------------------------
+		var sql_vals_pk []any
+		var the_query *string
+		var prepared_query *sql.Stmt
+		if a_chunk.begin_val_mysql != nil {
+			if a_chunk.end_val_mysql != nil {
+				sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.begin_val_mysql)
+				sql_vals_pk = append(sql_vals_pk, generateValuesForPredicat(last_table.indices_up_pk, a_chunk.end_val_mysql)...)
+				the_query = &last_table.interval_query
+				prepared_query = last_table.interval_prepared_stmt
+			} else {
+				sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.begin_val_mysql)
+				the_query = &last_table.lower_bound_query
+				prepared_query = last_table.lower_bound_prepared_stmt
+			}
+		} else {
+			sql_vals_pk = generateValuesForPredicat(last_table.indices_up_pk, a_chunk.end_val_mysql)
+			the_query = &last_table.upper_bound_query
+			prepared_query = last_table.upper_bound_prepared_stmt
+		}
+		ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 0)
+		q_rows, q_err := prepared_query.QueryContext(ctx, sql_vals_pk...)
+		if q_err != nil {
+			cancel()
+			if ctx_root.Err() != nil {
+				return
+			}
+			log.Printf("table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, a_chunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("ind lo: %v  ind up: %v", last_table.indices_lo_pk, last_table.indices_up_pk)
+			failf("can not query table %s to read the chunks\n%w", last_table.fullname, phaseError(ctx, q_err, last_table.fullname))
+		}
+		if mode_debug {
+			log.Printf("table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, a_chunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("ind lo: %v  ind up: %v", last_table.indices_lo_pk, last_table.indices_up_pk)
+		}
+		// --------------------------------------------------------------------------
+		dchunk := chunkReaderProcess(ctx_root, id, q_rows, &tableInfos[last_table.table_id], &a_chunk)
+		r_err := q_rows.Err()
+		cancel()
+		if r_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("can not read chunk %d of table %s\n%w", a_chunk.chunk_id, last_table.fullname, phaseError(ctx, r_err, last_table.fullname))
+		}
+		stats2push <- action2Stat{Action: Action_Read, Phase: Phase_SrcReader, TabId: last_table.table_id, Cnt: len(dchunk.rows)}
+		select {
+		case <-ctx_root.Done():
+			return
+		case chan2dstreader <- *dchunk:
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("table %s chunk %12d readrows %5d query :  %s \n with %d params\nval for query: %s\n", last_table.fullname, a_chunk.chunk_id, len(dchunk.rows), *the_query, len(sql_vals_pk), sql_vals_pk)
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+	for n := range tabReadingVars {
+		if tabReadingVars[n] != nil {
+			tabReadingVars[n].interval_prepared_stmt.Close()
+			tabReadingVars[n].lower_bound_prepared_stmt.Close()
+			tabReadingVars[n].upper_bound_prepared_stmt.Close()
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Printf("tableChunkReader  [%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+		log.Printf("tableChunkReader  [%02d]: finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func tableDstChunkReader(ctx_root context.Context, chunk2read chan syncdatachunk, chan2comparator chan datachunkpair, adbConn *sql.Conn, tableInfos []metadataTable, id int, cntBrowser int, driverDatabase string, stats2push chan action2Stat) {
+	mode_debug := isDebug(ctx_root)
+	if mode_debug {
+		log.Printf("tableDstChunkReader[%02d]: start\n", id)
+	}
+	var cntreadchunk int = 0
+	var last_hit int = 0
+	var cache_hit int = 0
+	var cache_miss int = 0
+
+	var tabReadingVars []*cacheSyncTableChunkReader
+
+	var last_table *cacheSyncTableChunkReader
+
+	tabReadingVars = make([]*cacheSyncTableChunkReader, cntBrowser+1)
+
+	for {
+		var a_chunk syncdatachunk
+		select {
+		case <-ctx_root.Done():
+			a_chunk.tchunk = nil
+		case a_chunk = <-chunk2read:
+		}
+		if a_chunk.tchunk == nil {
+			break
+		}
+		cntreadchunk++
+
+		if last_table == nil || last_table.table_id != a_chunk.tchunk.table_id {
+			// ------------------------------------------------------------------
+			var tab_found int = -1
+			var empty_slot int = -1
+			var lru_slot int = -1
+			var lru_val int = math.MaxInt
+			for n := range tabReadingVars {
+				if tabReadingVars[n] == nil {
+					empty_slot = n
+				} else if tabReadingVars[n].table_id == a_chunk.tchunk.table_id {
+					tab_found = n
+					break
+				} else {
+					if tabReadingVars[n].lastusagecnt < lru_val {
+						lru_val = tabReadingVars[n].lastusagecnt
+						lru_slot = n
+					}
+				}
+			}
+			if tab_found == -1 {
+				cache_miss++
+				// ----------------------------------------------------------
+				if empty_slot == -1 {
+					tabReadingVars[lru_slot].interval_prepared_stmt.Close()
+					tabReadingVars[lru_slot].lower_bound_prepared_stmt.Close()
+					tabReadingVars[lru_slot].upper_bound_prepared_stmt.Close()
+					tabReadingVars[lru_slot] = nil
+					empty_slot = lru_slot
+				}
+				// ----------------------------------------------------------
+				var new_info cacheSyncTableChunkReader
+
+				new_info.table_id = a_chunk.tchunk.table_id
+				new_info.lastusagecnt = cntreadchunk
+
+				new_info.fullname = tableInfos[a_chunk.tchunk.table_id].fullName
+				new_info.interval_query = tableInfos[a_chunk.tchunk.table_id].query_for_dst_reader_interval
+				new_info.lower_bound_query = tableInfos[a_chunk.tchunk.table_id].query_for_dst_reader_lower_bound
+				new_info.upper_bound_query = tableInfos[a_chunk.tchunk.table_id].query_for_dst_reader_upper_bound
+				new_info.indices_lo_pk = tableInfos[a_chunk.tchunk.table_id].param_indices_interval_lo_qry
+				new_info.indices_up_pk = tableInfos[a_chunk.tchunk.table_id].param_indices_interval_up_qry
+				// ------------------------------------------------------------------
+				ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				var p_err error
+				new_info.interval_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.interval_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableDstChunkReader[%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a chunk ( %s )\n%w", new_info.interval_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				ctx, cancel = phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				new_info.lower_bound_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.lower_bound_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableDstChunkReader[%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a lower bounded chunk ( %s )\n%w", new_info.lower_bound_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				ctx, cancel = phaseContext(ctx_root, timeout_chunkread, 2*time.Second)
+				new_info.upper_bound_prepared_stmt, p_err = adbConn.PrepareContext(ctx, new_info.upper_bound_query)
+				cancel()
+				if p_err != nil {
+					if ctx_root.Err() != nil {
+						return
+					}
+					log.Printf("tableDstChunkReader[%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+					failf("can not prepare to read a upper bounded chunk ( %s )\n%w", new_info.upper_bound_query, phaseError(ctx, p_err, new_info.fullname))
+				}
+				// ------------------------------------------------------------------
+				tabReadingVars[empty_slot] = &new_info
+				last_table = &new_info
+			} else {
+				cache_hit++
+				last_table = tabReadingVars[tab_found]
+				last_table.lastusagecnt = cntreadchunk
+			}
+		} else {
+			last_hit++
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			if driverDatabase == "mysql" {
+				log.Printf("tableDstChunkReader[%02d]: table %03d chunk %12d %s -> %s\n", id, a_chunk.tchunk.table_id, a_chunk.tchunk.chunk_id, a_chunk.tchunk.begin_val_mysql, a_chunk.tchunk.end_val_mysql)
+			} else {
+				log.Printf("tableDstChunkReader[%02d]: table %03d chunk %12d %s -> %s\n", id, a_chunk.tchunk.table_id, a_chunk.tchunk.chunk_id, a_chunk.tchunk.begin_val_oth, a_chunk.tchunk.end_val_oth)
+			}
+		}
+		// --------------------------------------------------------------------------
+		var sql_vals_pk []any
+		var the_query *string
+		var prepared_query *sql.Stmt
+
+		if a_chunk.tchunk.begin_val_mysql != nil {
+			if a_chunk.tchunk.end_val_mysql != nil {
+				if driverDatabase == "mysql" {
+					sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.tchunk.begin_val_mysql)
+					sql_vals_pk = append(sql_vals_pk, generateValuesForPredicat(last_table.indices_up_pk, a_chunk.tchunk.end_val_mysql)...)
+				} else {
+					sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.tchunk.begin_val_oth)
+					sql_vals_pk = append(sql_vals_pk, generateValuesForPredicat(last_table.indices_up_pk, a_chunk.tchunk.end_val_oth)...)
+				}
+				the_query = &last_table.interval_query
+				prepared_query = last_table.interval_prepared_stmt
+			} else {
+				if driverDatabase == "mysql" {
+					sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.tchunk.begin_val_mysql)
+				} else {
+					sql_vals_pk = generateValuesForPredicat(last_table.indices_lo_pk, a_chunk.tchunk.begin_val_oth)
+				}
+				the_query = &last_table.lower_bound_query
+				prepared_query = last_table.lower_bound_prepared_stmt
+			}
+		} else {
+			if driverDatabase == "mysql" {
+				sql_vals_pk = generateValuesForPredicat(last_table.indices_up_pk, a_chunk.tchunk.end_val_mysql)
+			} else {
+				sql_vals_pk = generateValuesForPredicat(last_table.indices_up_pk, a_chunk.tchunk.end_val_oth)
+			}
+			the_query = &last_table.upper_bound_query
+			prepared_query = last_table.upper_bound_prepared_stmt
+		}
+		ctx, cancel := phaseContext(ctx_root, timeout_chunkread, 0)
+		q_rows, q_err := prepared_query.QueryContext(ctx, sql_vals_pk...)
+		if q_err != nil {
+			cancel()
+			if ctx_root.Err() != nil {
+				return
+			}
+			log.Printf("tableDstChunkReader[%02d]: table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", id, last_table.fullname, a_chunk.tchunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("tableDstChunkReader[%02d]: ind lo: %v  ind up: %v", id, last_table.indices_lo_pk, last_table.indices_up_pk)
+			failf("tableDstChunkReader[%02d]: can not query table %s to read the chunks\n%w", id, last_table.fullname, phaseError(ctx, q_err, last_table.fullname))
+		}
+		if mode_debug {
+			log.Printf("tableDstChunkReader[%02d]: table %s chunk id: %12d chunk query :  %s \n with %d params\nval for query: %s\n", id, last_table.fullname, a_chunk.tchunk.chunk_id, *the_query, len(sql_vals_pk), sql_vals_pk)
+			log.Printf("tableDstChunkReader[%02d]: ind lo: %v  ind up: %v", id, last_table.indices_lo_pk, last_table.indices_up_pk)
+		}
+		// --------------------------------------------------------------------------
+		n_chunk := chunkReaderProcess(ctx_root, id, q_rows, &tableInfos[last_table.table_id], a_chunk.tchunk)
+		r_err := q_rows.Err()
+		cancel()
+		if r_err != nil {
+			if ctx_root.Err() != nil {
+				return
+			}
+			failf("tableDstChunkReader[%02d]: can not read chunk %d of table %s\n%w", id, a_chunk.tchunk.chunk_id, last_table.fullname, phaseError(ctx, r_err, last_table.fullname))
+		}
+		stats2push <- action2Stat{Action: Action_Read, Phase: Phase_DstReader, TabId: last_table.table_id, Cnt: len(n_chunk.rows)}
+		select {
+		case <-ctx_root.Done():
+			return
+		case chan2comparator <- datachunkpair{c_src: &a_chunk, c_dst: n_chunk}:
+		}
+		// --------------------------------------------------------------------------
+		if mode_debug {
+			log.Printf("tableDstChunkReader[%02d]: table %s chunk query :  %s \n with %d params\nval for query: %s\n", id, last_table.fullname, *the_query, len(sql_vals_pk), sql_vals_pk)
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+	for n := range tabReadingVars {
+		if tabReadingVars[n] != nil {
+			tabReadingVars[n].interval_prepared_stmt.Close()
+			tabReadingVars[n].lower_bound_prepared_stmt.Close()
+			tabReadingVars[n].upper_bound_prepared_stmt.Close()
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Printf("tableDstChunkReader[%02d]: finish cntreadchunk:%9d last_hit: %9d cache_hit: %9d cache_miss:%9d\n", id, cntreadchunk, last_hit, cache_hit, cache_miss)
+		log.Printf("tableDstChunkReader[%02d]: finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func generateListPkColsFetch4Sync(col_pk []string, enumCols []string, tablequote string) string {
+	if len(col_pk) == 0 {
+		return ""
+	}
+	a_str := ""
+	for ctab := 0; ctab < len(col_pk); ctab++ {
+		col_is_enum := false
+		for e := range enumCols {
+			if enumCols[e] == col_pk[ctab] {
+				col_is_enum = true
+				break
+			}
+		}
+		if ctab > 0 {
+			a_str = a_str + ","
+		}
+		a_str = a_str + " " + quoteIdentifier(col_pk[ctab], tablequote) + " "
+		if col_is_enum {
+			a_str = a_str + " , cast(" + quoteIdentifier(col_pk[ctab], tablequote) + " as unsigned integer ) as " + quoteIdentifier("integer_of_"+col_pk[ctab], tablequote)
+		}
+	}
+	return a_str
+}
+
+// ------------------------------------------------------------------------------------------
+func compareRow(src_r *rowchunk, dst_r *rowchunk, colspk int, colscnt int, colskinddate *[]bool, mode_trace bool) (int, int) {
+	for c := 0; c < colspk; c++ {
+		if src_r.cols[c].Valid {
+			if dst_r.cols[c].Valid {
+				if mode_trace {
+					log.Printf("compareRow col %2d VAL src %-40s dst %-40s", c, src_r.cols[c].String, dst_r.cols[c].String)
+				}
+				if (*colskinddate)[c] {
+					t1, err1 := time.Parse(time.DateTime, src_r.cols[c].String)
+					if err1 != nil {
+						t1, err1 = time.Parse(time.RFC3339, src_r.cols[c].String)
+						if err1 != nil {
+							failf("can convert this '%s' to time.Time", src_r.cols[c].String)
+						}
+					}
+					t2, err2 := time.Parse(time.DateTime, dst_r.cols[c].String)
+					if err2 != nil {
+						t2, err2 = time.Parse(time.RFC3339, dst_r.cols[c].String)
+						if err2 != nil {
+							failf("can convert this '%s' to time.Time", src_r.cols[c].String)
+						}
+					}
+					if t1.Before(t2) {
+						return -1, -1
+					}
+					if t1.After(t2) {
+						return 1, 1
+					}
+				} else {
+					if src_r.cols[c].String < dst_r.cols[c].String {
+						return -1, -1
+					}
+					if src_r.cols[c].String > dst_r.cols[c].String {
+						return 1, 1
+					}
+				}
+			} else {
+				return 1, 1
+			}
+		} else {
+			if dst_r.cols[c].Valid {
+				return -1, -1
+			}
+		}
+	}
+
+	for c := colspk; c < colscnt; c++ {
+		if src_r.cols[c].Valid {
+			if dst_r.cols[c].Valid {
+				if mode_trace {
+					log.Printf("compareRow col %2d VAL src %-40s/%-80s dst %-40s %-80s", c, src_r.cols[c].String, dst_r.cols[c].String, hex.EncodeToString([]byte(src_r.cols[c].String)), hex.EncodeToString([]byte(dst_r.cols[c].String)))
+				}
+				if (*colskinddate)[c] {
+					t1, err1 := time.Parse(time.DateTime, src_r.cols[c].String)
+					if err1 != nil {
+						t1, err1 = time.Parse(time.RFC3339, src_r.cols[c].String)
+						if err1 != nil {
+							failf("can convert this '%s' to time.Time", src_r.cols[c].String)
+						}
+					}
+					t2, err2 := time.Parse(time.DateTime, dst_r.cols[c].String)
+					if err2 != nil {
+						t2, err2 = time.Parse(time.RFC3339, dst_r.cols[c].String)
+						if err2 != nil {
+							failf("can convert this '%s' to time.Time", src_r.cols[c].String)
+						}
+					}
+					if t1.Before(t2) {
+						return -1, -1
+					}
+					if t1.After(t2) {
+						return 1, 1
+					}
+				} else {
+					if src_r.cols[c].String < dst_r.cols[c].String {
+						return 0, -1
+					}
+					if src_r.cols[c].String > dst_r.cols[c].String {
+						return 0, 1
+					}
+				}
+			} else {
+				return 0, 1
+			}
+		} else {
+			if dst_r.cols[c].Valid {
+				return 0, -1
+			}
+		}
+	}
+	return 0, 0
+}
+
+// ------------------------------------------------------------------------------------------
+func dataChunkComparator(ctx_root context.Context, rowvalueschan chan datachunkpair, id int, tableInfos []metadataTable, sql2generate chan datarow, dst_driver string, cntBrowser int) {
+	mode_trace := isTrace(ctx_root)
+	// ----------------------------------------------------------------------------------
+	for {
+		var pair_chunks datachunkpair
+		select {
+		case <-ctx_root.Done():
+			pair_chunks = datachunkpair{c_src: nil, c_dst: nil}
+		case pair_chunks = <-rowvalueschan:
+		}
+		if pair_chunks.c_src == nil && pair_chunks.c_dst == nil {
+			break
+		}
+		// --------------------------------------------------------------------------
+		cntCols := tableInfos[pair_chunks.c_src.tchunk.table_id].cntCols
+		cntPkCols := tableInfos[pair_chunks.c_src.tchunk.table_id].cntPkCols
+		if mode_trace {
+			if dst_driver == "mysql" {
+				log.Printf("dataChunkComparator[%02d]: chunk %d begin pk %s end pk %s ", id, pair_chunks.c_src.tchunk.chunk_id, pair_chunks.c_src.tchunk.begin_val_mysql, pair_chunks.c_src.tchunk.end_val_mysql)
+			} else {
+				log.Printf("dataChunkComparator[%02d]: chunk %d begin pk %s end pk %s ", id, pair_chunks.c_src.tchunk.chunk_id, pair_chunks.c_src.tchunk.begin_val_oth, pair_chunks.c_src.tchunk.end_val_oth)
+			}
+			log.Printf("dataChunkComparator[%02d]: chunk %d rows src %05d dst %05d ", id, pair_chunks.c_src.tchunk.chunk_id, len(pair_chunks.c_src.rows), len(pair_chunks.c_dst.rows))
+		}
+		// ---------------
+		colIsDate := make([]bool, cntCols)
+		for i, v := range tableInfos[pair_chunks.c_src.tchunk.table_id].columnInfos {
+			colIsDate[i] = v.isKindDate
+		}
+		// --------------------------------------------------------------------------
+		sort.SliceStable(pair_chunks.c_src.rows,
+			func(i, j int) bool {
+				for c := 0; c < cntCols; c++ {
+					if pair_chunks.c_src.rows[i].cols[c].Valid {
+						if pair_chunks.c_src.rows[j].cols[c].Valid {
+							if pair_chunks.c_src.rows[i].cols[c].String < pair_chunks.c_src.rows[j].cols[c].String {
+								return true
+							}
+							if pair_chunks.c_src.rows[i].cols[c].String > pair_chunks.c_src.rows[j].cols[c].String {
+								return false
+							}
+						} else {
+							return false
+						}
+					} else {
+						if pair_chunks.c_src.rows[j].cols[c].Valid {
+							return true
+						}
+					}
+				}
+				return false
+			})
+		sort.SliceStable(pair_chunks.c_dst.rows,
+			func(i, j int) bool {
+				for c := 0; c < cntCols; c++ {
+					if pair_chunks.c_dst.rows[i].cols[c].Valid {
+						if pair_chunks.c_dst.rows[j].cols[c].Valid {
+							if pair_chunks.c_dst.rows[i].cols[c].String < pair_chunks.c_dst.rows[j].cols[c].String {
+								return true
+							}
+							if pair_chunks.c_dst.rows[i].cols[c].String > pair_chunks.c_dst.rows[j].cols[c].String {
+								return false
+							}
+						} else {
+							return false
+						}
+					} else {
+						if pair_chunks.c_dst.rows[j].cols[c].Valid {
+							return true
+						}
+					}
+				}
+				return false
+			})
+		// --------------------------------------------------------------------------
+		cur_src, cur_dst := 0, 0
+		for cur_src < len(pair_chunks.c_src.rows) || cur_dst < len(pair_chunks.c_dst.rows) {
+			// ------------------------------------------------------------------
+			if mode_trace {
+				log.Printf("dataChunkComparator[%02d]: chunk %d  | cur src %5d dst %5d | len src %5d dst %5d", id, pair_chunks.c_src.tchunk.chunk_id, cur_src, cur_dst, len(pair_chunks.c_src.rows), len(pair_chunks.c_dst.rows))
+			}
+			// ------------------------------------------------------------------
+			if cur_src == len(pair_chunks.c_src.rows) {
+				select {
+				case <-ctx_root.Done():
+					return
+				case sql2generate <- datarow{table_id: pair_chunks.c_src.tchunk.table_id, dmltype: DML_Delete, whrdta: pair_chunks.c_dst.rows[cur_dst]}:
+				}
+				cur_dst++
+				continue
+			}
+			if cur_dst == len(pair_chunks.c_dst.rows) {
+				select {
+				case <-ctx_root.Done():
+					return
+				case sql2generate <- datarow{table_id: pair_chunks.c_src.tchunk.table_id, dmltype: DML_Insert, valdta: pair_chunks.c_src.rows[cur_src]}:
+				}
+				cur_src++
+				continue
+			}
+			// ------------------------------------------------------------------
+			cmp_pk, cmp_row := compareRow(pair_chunks.c_src.rows[cur_src], pair_chunks.c_dst.rows[cur_dst], cntPkCols, cntCols, &colIsDate, mode_trace)
+			// ------------------------------------------------------------------
+			if mode_trace {
+				log.Printf("dataChunkComparator[%02d]: chunk %d  | cur src %5d dst %5d | len src %5d dst %5d | cmp pk %2d whole %2d ", id, pair_chunks.c_src.tchunk.chunk_id, cur_src, cur_dst, len(pair_chunks.c_src.rows), len(pair_chunks.c_dst.rows), cmp_pk, cmp_row)
+			}
+			// ------------------------------------------------------------------
+			if cmp_pk == 0 && cmp_row == 0 {
+				cur_src++
+				cur_dst++
+				continue
+			}
+			// ------------------------------------------------------------------
+			if cmp_pk == -1 {
+				select {
+				case <-ctx_root.Done():
+					return
+				case sql2generate <- datarow{table_id: pair_chunks.c_src.tchunk.table_id, dmltype: DML_Insert, valdta: pair_chunks.c_src.rows[cur_src]}:
+				}
+				cur_src++
+				continue
+			}
+			// ------------------------------------------------------------------
+			if cmp_pk == 1 {
+				select {
+				case <-ctx_root.Done():
+					return
+				case sql2generate <- datarow{table_id: pair_chunks.c_src.tchunk.table_id, dmltype: DML_Delete, whrdta: pair_chunks.c_dst.rows[cur_dst]}:
+				}
+				cur_dst++
+				continue
+			}
+			if cmp_pk == 0 && cmp_row != 0 {
+				select {
+				case <-ctx_root.Done():
+					return
+				case sql2generate <- datarow{table_id: pair_chunks.c_src.tchunk.table_id, dmltype: DML_Update, valdta: pair_chunks.c_src.rows[cur_src], whrdta: pair_chunks.c_dst.rows[cur_dst]}:
+				}
+				cur_src++
+				cur_dst++
+				continue
+			}
+			// ------------------------------------------------------------------
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func dataSqlGenerator(ctx_root context.Context, rowtomodify chan datarow, id int, tableInfos []metadataTable, sql2execute chan syncinsertchunk, dst_driver string, cntBrowser int) {
+	mode_trace := isTrace(ctx_root)
+	// ----------------------------------------------------------------------------------
+	var param_pattern string
+	var where_param_pattern string
+	if dst_driver == "mysql" {
+		param_pattern = "?"
+		where_param_pattern = "= ?"
+	}
+	if dst_driver == "postgres" {
+		param_pattern = "$"
+		where_param_pattern = "= $"
+	}
+	if dst_driver == "mssql" {
+		param_pattern = "@p"
+		where_param_pattern = "= @p"
+	}
+	// ----------------------------------------------------------------------------------
+	for {
+		var a_dml_row datarow
+		select {
+		case <-ctx_root.Done():
+			a_dml_row.table_id = -1
+		case a_dml_row = <-rowtomodify:
+		}
+		if a_dml_row.table_id == -1 {
+			break
+		}
+		// --------------------------------------------------------------------------
+		no_null_cnt := 0
+		no_null_whr_cnt := 0
+		if a_dml_row.dmltype == DML_Insert || a_dml_row.dmltype == DML_Update {
+			for _, v := range a_dml_row.valdta.cols {
+				if v.Valid {
+					no_null_cnt += 1
+				}
+			}
+		}
+		if a_dml_row.dmltype == DML_Update || a_dml_row.dmltype == DML_Delete {
+			for _, v := range a_dml_row.whrdta.cols {
+				if v.Valid {
+					no_null_whr_cnt += 1
+				}
+			}
+		}
+		var sqlParams []any = make([]any, no_null_cnt+no_null_whr_cnt)
+		var qryPlaceHolders []string = make([]string, no_null_cnt+no_null_whr_cnt)
+		// ----------------------------------------------------------
+		q_siz := 0
+		if dst_driver == "mysql" {
+			param_pos := 0
+			if a_dml_row.dmltype == DML_Insert || a_dml_row.dmltype == DML_Update {
+				for _, v := range a_dml_row.valdta.cols {
+					if !v.Valid {
+						qryPlaceHolders[param_pos] = "NULL"
+						q_siz += 4
+					} else {
+						qryPlaceHolders[param_pos] = param_pattern
+						sqlParams[param_pos] = v.String
+						param_pos += 1
+						q_siz += 1
+					}
+				}
+			}
+			if a_dml_row.dmltype == DML_Update || a_dml_row.dmltype == DML_Delete {
+				for _, v := range a_dml_row.whrdta.cols {
+					if !v.Valid {
+						qryPlaceHolders[param_pos] = " IS NULL"
+						q_siz += 8
+					} else {
+						qryPlaceHolders[param_pos] = where_param_pattern
+						sqlParams[param_pos] = v.String
+						param_pos += 1
+						q_siz += 3
+					}
+				}
+			}
+		} else {
+			param_pos := 0
+			if a_dml_row.dmltype == DML_Insert || a_dml_row.dmltype == DML_Update {
+				for n, v := range a_dml_row.valdta.cols {
+					if !v.Valid {
+						qryPlaceHolders[param_pos] = "NULL"
+						q_siz += 4
+					} else {
+						str_val := param_pattern + strconv.Itoa(param_pos+1)
+						qryPlaceHolders[param_pos] = str_val
+						if tableInfos[a_dml_row.table_id].columnInfos[n].isKindBinary {
+							sqlParams[param_pos] = []byte(v.String)
+						} else {
+							if dst_driver == "postgres" && strings.IndexByte(v.String, 0) != -1 {
+								sqlParams[param_pos] = strings.ReplaceAll(v.String, "\x00", "")
+							} else {
+								sqlParams[param_pos] = v.String
+							}
+						}
+						param_pos += 1
+						q_siz += len(str_val)
+					}
+				}
+			}
+			if a_dml_row.dmltype == DML_Update || a_dml_row.dmltype == DML_Delete {
+				for n, v := range a_dml_row.whrdta.cols {
+					if !v.Valid {
+						qryPlaceHolders[param_pos] = " IS NULL"
+						q_siz += 8
+					} else {
+						str_val := where_param_pattern + strconv.Itoa(param_pos+1)
+						qryPlaceHolders[param_pos] = str_val
+						if tableInfos[a_dml_row.table_id].columnInfos[n].isKindBinary {
+							sqlParams[param_pos] = []byte(v.String)
+						} else {
+							if dst_driver == "postgres" && strings.IndexByte(v.String, 0) != -1 {
+								sqlParams[param_pos] = strings.ReplaceAll(v.String, "\x00", "")
+							} else {
+								sqlParams[param_pos] = v.String
+							}
+						}
+						param_pos += 1
+						q_siz += len(str_val)
+					}
+				}
+			}
+		}
+		// --------------------------------------------------------------------------
+		var b strings.Builder
+		var estim_size int
+		if a_dml_row.dmltype == DML_Insert {
+			estim_size = tableInfos[a_dml_row.table_id].query_for_insert_size + q_siz
+		}
+		if a_dml_row.dmltype == DML_Update {
+			estim_size = tableInfos[a_dml_row.table_id].query_for_update_size + q_siz
+		}
+		if a_dml_row.dmltype == DML_Delete {
+			estim_size = tableInfos[a_dml_row.table_id].query_for_delete_size + q_siz
+		}
+		b.Grow(estim_size)
+		if a_dml_row.dmltype == DML_Insert {
+			if mode_trace {
+				log.Printf("dataSqlGenerator[%d] size q_ins %d size p_hld %d\n", id, len(tableInfos[a_dml_row.table_id].query_for_insert), len(qryPlaceHolders))
+			}
+			b.WriteString(tableInfos[a_dml_row.table_id].query_for_insert[0])
+			for n, v := range qryPlaceHolders {
+				b.WriteString(v)
+				b.WriteString(tableInfos[a_dml_row.table_id].query_for_insert[n+1])
+			}
+		}
+		if a_dml_row.dmltype == DML_Update {
+			b.WriteString(tableInfos[a_dml_row.table_id].query_for_update[0])
+			for n, v := range qryPlaceHolders {
+				b.WriteString(v)
+				b.WriteString(tableInfos[a_dml_row.table_id].query_for_update[n+1])
+			}
+		}
+		if a_dml_row.dmltype == DML_Delete {
+			b.WriteString(tableInfos[a_dml_row.table_id].query_for_delete[0])
+			for n, v := range qryPlaceHolders {
+				b.WriteString(v)
+				b.WriteString(tableInfos[a_dml_row.table_id].query_for_delete[n+1])
+			}
+		}
+		// --------------------------------------------------------------------------
+		a_str := b.String()
+		select {
+		case <-ctx_root.Done():
+			return
+		case sql2execute <- syncinsertchunk{table_id: a_dml_row.table_id, sql: &a_str, params: &sqlParams, cntrows: 1, dmltype: a_dml_row.dmltype}:
+		}
+		// --------------------------------------------------------------------------
+	}
+	// ----------------------------------------------------------------------------------
+}
+
+// ------------------------------------------------------------------------------------------
+func tableDstDbWriter(ctx_root context.Context, sql2inject chan syncinsertchunk, adbConn *sql.Conn, id int, tableInfos []metadataTable, stats2push chan action2Stat, dont_do_insert bool, dont_do_update bool, dont_do_delete bool) {
+	mode_debug := isDebug(ctx_root)
+	mode_trace := isTrace(ctx_root)
+	if mode_debug {
+		log.Printf("tableDstDbWriter   [%02d]: start\n", id)
+	}
+	if dont_do_insert {
+		log.Printf("tableDstDbWriter   [%02d]: do INSERT\n", id)
+	}
+	if dont_do_update {
+		log.Printf("tableDstDbWriter   [%02d]: no UPDATE\n", id)
+	}
+	if dont_do_delete {
+		log.Printf("tableDstDbWriter   [%02d]: no DELETE\n", id)
+	}
+	// ----------------------------------------------------------------------------------
+	var a_insert_sql syncinsertchunk
+	select {
+	case <-ctx_root.Done():
+		a_insert_sql.table_id = -1
+	case a_insert_sql = <-sql2inject:
+	}
+	for a_insert_sql.table_id != -1 {
+		// --------------------------------------------------------------------------
+		if mode_trace {
+			log.Printf("tableDstDbWriter   [%02d]: dmltype %d cnt %d \n", id, a_insert_sql.dmltype, a_insert_sql.cntrows)
+		}
+		if (dont_do_insert && a_insert_sql.dmltype == DML_Insert) || (dont_do_update && a_insert_sql.dmltype == DML_Update) || (dont_do_delete && a_insert_sql.dmltype == DML_Delete) {
+			stats2push <- action2Stat{Action: Action_NoOp, Phase: Phase_DstWriter, TabId: a_insert_sql.table_id, Cnt: a_insert_sql.cntrows}
+		} else {
+			table_name := tableInfos[a_insert_sql.table_id].fullName
+			ctx, cancel := phaseContext(ctx_root, timeout_write, 0)
+			if a_insert_sql.params != nil {
+				_, e_err := adbConn.ExecContext(ctx, *a_insert_sql.sql, *a_insert_sql.params...)
+				cancel()
+				if e_err != nil {
+					if ctx_root.Err() != nil {
+						break
+					}
+					log.Printf("error with :\n%s", *a_insert_sql.sql)
+					log.Printf("with params array of %d elems", len(*a_insert_sql.params))
+					failf("thread %d , can not insert/update/delete a chunk\n%w", id, phaseError(ctx, e_err, table_name))
+				}
+			} else {
+				_, e_err := adbConn.ExecContext(ctx, *a_insert_sql.sql)
+				cancel()
+				if e_err != nil {
+					if ctx_root.Err() != nil {
+						break
+					}
+					log.Printf("error with :\n%s", *a_insert_sql.sql)
+					failf("thread %d , can not insert/update/delete a chunk\n%w", id, phaseError(ctx, e_err, table_name))
+				}
+			}
+			stats2push <- action2Stat{Action: Action_Write, Phase: Phase_DstWriter, TabId: a_insert_sql.table_id, Cnt: a_insert_sql.cntrows}
+		}
+		// --------------------------------------------------------------------------
+		select {
+		case <-ctx_root.Done():
+			a_insert_sql.table_id = -1
+		case a_insert_sql = <-sql2inject:
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Printf("tableDstDbWriter   [%02d]: finish\n", id)
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+func tableStatsAction(run_ctx context.Context, stat2summarize chan action2Stat, tableInfos []metadataTable, stats_file *os.File, progress ProgressFunc) {
+	mode_debug := isDebug(run_ctx)
+	// ----------------------------------------------------------------------------------
+	if mode_debug {
+		log.Print("tableStatsAction   [00]: start\n")
+	}
+	all_stats := make([]action2Stat, 0)
+	for j := range tableInfos {
+		all_stats = append(all_stats, action2Stat{Action: Action_Read, Phase: Phase_Browser, TabId: j, Cnt: 0})
+	}
+	// ----------------------------------------------------------------------------------
+	stat2add := <-stat2summarize
+	for stat2add.TabId != -1 {
+		// --------------------------------------------------------------------------
+		s_found := -1
+		for i, s := range all_stats {
+			if s.Action == stat2add.Action && s.Phase == stat2add.Phase && s.TabId == stat2add.TabId {
+				s_found = i
+				break
+			}
+		}
+		if s_found == -1 {
+			all_stats = append(all_stats, stat2add)
+		} else {
+			all_stats[s_found].Cnt = all_stats[s_found].Cnt + stat2add.Cnt
+		}
+		progress_stage := ""
+		switch {
+		case stat2add.Action == Action_Read && stat2add.Phase == Phase_Browser:
+			progress_stage = Progress_ChunkBrowsed
+		case stat2add.Action == Action_Read && stat2add.Phase == Phase_SrcReader:
+			progress_stage = Progress_ChunkRead
+		case stat2add.Action == Action_Read && stat2add.Phase == Phase_DstReader:
+			progress_stage = Progress_DstChunkRead
+		case stat2add.Action == Action_Write && stat2add.Phase == Phase_DstWriter:
+			progress_stage = Progress_RowsWritten
+		case stat2add.Action == Action_NoOp && stat2add.Phase == Phase_DstWriter:
+			progress_stage = Progress_RowsUnchanged
+		}
+		if len(progress_stage) > 0 {
+			progress.emit(ProgressEvent{Stage: progress_stage, Table: tableInfos[stat2add.TabId].fullName, Rows: int64(stat2add.Cnt)})
+		}
+		// --------------------------------------------------------------------------
+		stat2add = <-stat2summarize
+	}
+	// ----------------------------------------------------------------------------------
+	deja_vu_table := make([]int, 0)
+	for _, s := range all_stats {
+		// --------------------------------------------------------------------------
+		t_found := false
+		for _, t := range deja_vu_table {
+			if t == s.TabId {
+				t_found = true
+				break
+			}
+		}
+		// --------------------------------------------------------------------------
+		if !t_found {
+			src_brow := 0
+			src_read := 0
+			dst_read := 0
+			dst_writ := 0
+			dst_noop := 0
+			for _, sa := range all_stats {
+				if sa.TabId == s.TabId {
+					if sa.Action == Action_Read && sa.Phase == Phase_SrcReader {
+						src_read = sa.Cnt
+					} else {
+						if sa.Action == Action_Read && sa.Phase == Phase_DstReader {
+							dst_read = sa.Cnt
+						} else {
+							if sa.Action == Action_Write && sa.Phase == Phase_DstWriter {
+								dst_writ = sa.Cnt
+							} else {
+								if sa.Action == Action_Read && sa.Phase == Phase_Browser {
+									src_brow = sa.Cnt
+								} else {
+									if sa.Action == Action_NoOp && sa.Phase == Phase_DstWriter {
+										dst_noop = sa.Cnt
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+			stat_table := fmt.Sprintf("%-32s : src brw %9d read %9d / dst read %9d noop %9d wrt %9d\n", tableInfos[s.TabId].tbName, src_brow, src_read, dst_read, dst_noop, dst_writ)
+			log.Printf("tableStatsAction   [00]: %s", stat_table)
+			if stats_file != nil {
+				stats_file.WriteString(stat_table)
+			}
+			deja_vu_table = append(deja_vu_table, s.TabId)
+		}
+		// --------------------------------------------------------------------------
+	}
+	if mode_debug {
+		log.Print("tableStatsAction   [00]: finish\n")
+	}
+	if stats_file != nil {
+		stats_file.Close()
+	}
+}
+
+// ------------------------------------------------------------------------------------------
+// SyncOptions are the settings of a Syncer , they match the flags of parasync
+//
+// DstSchema is Schema when empty , Tables and ExcludeTables are table patterns ( table ,
+// schema.table , shell globs or /regex/ ) , Where are table filters ( pattern:expression ) ,
+// the expression is used on the source and on the destination . Columns and ExcludeColumns
+// are column lists ( pattern:col1,col2 ) , the other columns are not compared and not
+// written , the columns of the primary key are always compared . StatsFile is where the
+// counters of each table are written at the end , nothing is written when empty
+type SyncOptions struct {
+	Source          DbServer
+	Destination     DbServer
+	SnapshotLock    string
+	Schema          string
+	DstSchema       string
+	Tables          []string
+	ExcludeTables   []string
+	Where           []string
+	Columns         []string
+	ExcludeColumns  []string
+	GuessPrimaryKey bool
+	Browsers        int
+	Readers         int
+	DstReaders      int
+	DstWriters      int
+	ChunkSize       int
+	InsertSize      int
+	StatsFile       string
+	NoInsert        bool
+	NoUpdate        bool
+	NoDelete        bool
+	Debug           bool
+	Trace           bool
+	Timeouts        Timeouts
+	Progress        ProgressFunc
+}
+
+// ------------------------------------------------------------------------------------------
+type Syncer struct {
+	opts SyncOptions
+}
+
+func NewSyncer(opts SyncOptions) *Syncer {
+	return &Syncer{opts: opts}
+}
+
+// ------------------------------------------------------------------------------------------
+// Run returns the first failure , or ctx.Err() when ctx is cancelled , in both cases the
+// destination is partially synchronized
+func (s *Syncer) Run(ctx context.Context) (ret_err error) {
+	o := s.opts
+	// the settings of the run go down with its contexts , the setup stops with ctx ,
+	// release_ctx is not cancelled with ctx , for the sessions of the snapshot that must be
+	// given back
+	ctx = withRunSettings(ctx, o.Debug, o.Trace, o.Timeouts)
+	release_ctx := detachedContext(ctx)
+	mode_debug := isDebug(ctx)
+	mode_trace := isTrace(ctx)
+	if len(o.Schema) == 0 {
+		return fmt.Errorf("no schema specified")
+	}
+	if len(o.Tables) == 0 {
+		return fmt.Errorf("no tables specified")
+	}
+	for _, patterns := range [][]string{o.Tables, o.ExcludeTables} {
+		if _, p_err := parseTablePatterns(patterns); p_err != nil {
+			return p_err
+		}
+	}
+	if _, w_err := parseTableWheres(o.Where); w_err != nil {
+		return w_err
+	}
+	for _, lists := range [][]string{o.Columns, o.ExcludeColumns} {
+		if _, c_err := parseTableColumns(lists); c_err != nil {
+			return c_err
+		}
+	}
+	if len(o.DstSchema) == 0 {
+		o.DstSchema = o.Schema
+	}
+	// ----------------------------------------------------------------------------------
+	var cntBrowser int = o.Browsers
+	var cntReader int = o.Readers
+	// ----------------------------------------------------------------------------------
+	var conSrc []*sql.Conn
+	var conDst []*sql.Conn
+	var dbSrc *sql.DB
+	var dbDst *sql.DB
+	var c_err error
+	switch o.Source.Driver {
+	case "mysql":
+		dbSrc, conSrc, _, c_err = getaSynchronizedMysqlConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, o.Schema, o.Source.Options, o.SnapshotLock, false)
+	case "mssql":
+		dbSrc, conSrc, _, c_err = getaSynchronizedMsSqlConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, o.Source.Database, o.Source.Options)
+	case "postgres":
+		dbSrc, conSrc, _, c_err = getaSynchronizedPostgresConnections(ctx, o.Source.Host, o.Source.Port, o.Source.User, o.Source.Password, cntReader+cntBrowser, o.Source.Database, o.Source.Options)
+	default:
+		return fmt.Errorf("unknown driver %s", o.Source.Driver)
+	}
+	if c_err != nil {
+		return c_err
+	}
+	defer dbSrc.Close()
+	switch o.Destination.Driver {
+	case "mysql":
+		dbDst, conDst, c_err = getDstMysqlConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, o.DstReaders+o.DstWriters, o.DstSchema, o.Destination.Options)
+	case "mssql":
+		dbDst, conDst, c_err = getDstMsSqlConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, o.DstReaders+o.DstWriters, o.Destination.Database, o.Destination.Options)
+	case "postgres":
+		dbDst, conDst, c_err = getDstPostgresConnections(ctx, o.Destination.Host, o.Destination.Port, o.Destination.User, o.Destination.Password, o.DstReaders+o.DstWriters, o.Destination.Database, o.Destination.Options)
+	default:
+		c_err = fmt.Errorf("unknown driver %s", o.Destination.Driver)
+	}
+	if c_err != nil {
+		releaseSourceConnections(release_ctx, o.Source.Driver, conSrc)
+		return c_err
+	}
+	defer dbDst.Close()
+	o.Progress.emit(ProgressEvent{Stage: Progress_Connected})
+	// ----------------------------------------------------------------------------------
+	// from here a failure must give back the snapshot of the source
+	defer onFailure(&ret_err, func() { releaseSourceConnections(release_ctx, o.Source.Driver, conSrc) })
+	// ----------------------------------------------------------------------------------
+	tables2sync, l_err := getListTables(ctx, conSrc[0], []string{o.Schema}, o.Tables, o.ExcludeTables, o.Source.Driver)
+	if l_err != nil {
+		return l_err
+	}
+	if w_err := setTableWheres(tables2sync, o.Where); w_err != nil {
+		return w_err
+	}
+	if c_err := setTableColumns(tables2sync, o.Columns, o.ExcludeColumns); c_err != nil {
+		return c_err
+	}
+	for n := range tables2sync {
+		tables2sync[n].dstDbName = o.DstSchema
+	}
+	if mode_debug {
+		log.Print(tables2sync)
+	}
+	r, m_err := getSyncMetadataInfo4Tables(ctx, conSrc[0], tables2sync, o.GuessPrimaryKey, o.Source.Driver, o.Destination.Driver)
+	if m_err != nil {
+		return m_err
+	}
+	if mode_debug {
+		log.Printf("tables infos  => %v", r)
+	}
+	if t_err := checkSyncTablesOnDestination(ctx, o.Source.Driver, o.Destination.Driver, conDst[0], &r); t_err != nil {
+		return t_err
+	}
+	// ---------------------------------
+	for i := 0; i < len(r); i++ {
+		r[i].insert_size = o.InsertSize
+		if o.Destination.Driver == "mssql" {
+			if r[i].cntCols*o.InsertSize >= 2100 {
+				r[i].insert_size = (2100 - 1) / r[i].cntCols
+				log.Printf("we change insertsize for %s from %d to %d ", r[i].fullName, o.InsertSize, r[i].insert_size)
+			}
+		}
+		o.Progress.emit(ProgressEvent{Stage: Progress_Metadata, Table: r[i].fullName, Rows: r[i].cntRows})
+	}
+	// ----------------------------------------------------------------------------------
+	var stats_file *os.File
+	if len(o.StatsFile) > 0 {
+		var o_err error
+		stats_file, o_err = os.OpenFile(o.StatsFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if o_err != nil {
+			return fmt.Errorf("can not openfile %s\n%w", o.StatsFile, o_err)
+		}
+	}
+	// ----------------------------------------------------------------------------------
+	if ctx.Err() != nil {
+		if stats_file != nil {
+			stats_file.Close()
+		}
+		log.Print("sync interrupted before any table was read")
+		return ctx.Err()
+	}
+	// ----------------------------------------------------------------------------------
+	// we use chan to communicate between all go subroutines
+	//
+	tables_to_browse := make(chan int, len(tables2sync)+cntBrowser)
+	pk_chunks_to_read := make(chan synctablechunk, o.Readers*200)
+	chunks_to_read_on_dst := make(chan syncdatachunk, o.DstReaders*200)
+	sql_to_write := make(chan syncinsertchunk, o.Readers*400)
+	chunk_comparator := make(chan datachunkpair, o.Readers*1000)
+	sql_generator := make(chan datarow, o.Readers*3000)
+	stat_monitor := make(chan action2Stat, 10000)
+	// ------------
+	if mode_debug {
+		pk_chunks_to_read = make(chan synctablechunk, 5)
+		chunks_to_read_on_dst = make(chan syncdatachunk, 5)
+		sql_to_write = make(chan syncinsertchunk, 5)
+		chunk_comparator = make(chan datachunkpair, 5)
+		sql_generator = make(chan datarow, 10)
+	}
+	if mode_trace {
+		pk_chunks_to_read = make(chan synctablechunk, 1)
+		chunks_to_read_on_dst = make(chan syncdatachunk, 1)
+		sql_to_write = make(chan syncinsertchunk, 1)
+		chunk_comparator = make(chan datachunkpair, 1)
+		sql_generator = make(chan datarow, 1)
+	}
+	// ------------
+	for t := 0; t < len(tables2sync); t++ {
+		tables_to_browse <- t
+	}
+	for b := 0; b < cntBrowser; b++ {
+		tables_to_browse <- -1
+	}
+	// ----------------------------------------------------------------------------------
+	//
+	// browser    : scan tables on db to compute border of each chunk  and pass to a reader
+	// reader     : read chunks quickly from the db pass them to dst-reader
+	// dst-reader : from a chunk on source , read the same chink on destination , pass both to the comparator
+	// comparator : with a pair chunk , compare them , and find difference between source and destination , pass to the generator
+	// generator  : from a difference generate a sql code and pass to writer
+	// writer     : receive row action , I U D , and inject into a db
+	//
+	// the first failure of a stage cancels ctx_root , every stage stops as on a signal
+	g := newFatalGroup(ctx)
+	defer g.cancel()
+	ctx_root := g.ctx
+	var wg_brw sync.WaitGroup
+	var wg_red sync.WaitGroup
+	var wg_dst_red sync.WaitGroup
+	var wg_cmp sync.WaitGroup
+	var wg_gen sync.WaitGroup
+	var wg_dst_wrt sync.WaitGroup
+	var wg_mon sync.WaitGroup
+	// ----------------------------------------------------------------------------------
+	wg_mon.Add(1)
+	go func() {
+		defer wg_mon.Done()
+		tableStatsAction(ctx, stat_monitor, r, stats_file, o.Progress)
+	}()
+	// ------------
+	for j := 0; j < cntBrowser; j++ {
+		wg_brw.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_brw.Done()
+			defer g.catch()
+			syncTableChunkBrowser(ctx_root, adbConn, id, tables_to_browse, r, pk_chunks_to_read, o.ChunkSize, stat_monitor)
+		}(conSrc[j], j)
+	}
+	// ------------
+	for j := 0; j < cntReader; j++ {
+		time.Sleep(10 * time.Millisecond)
+		wg_red.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_red.Done()
+			defer g.catch()
+			syncTableChunkReader(ctx_root, pk_chunks_to_read, chunks_to_read_on_dst, adbConn, r, id, cntBrowser, stat_monitor)
+		}(conSrc[j+cntBrowser], j)
+	}
+	// ------------
+	cntComparator := o.Readers * 2
+	if mode_debug {
+		cntComparator = 2
+		if mode_trace {
+			cntComparator = 1
+		}
+	}
+	for j := 0; j < cntComparator; j++ {
+		wg_cmp.Add(1)
+		go func(id int) {
+			defer wg_cmp.Done()
+			defer g.catch()
+			dataChunkComparator(ctx_root, chunk_comparator, id, r, sql_generator, o.Destination.Driver, cntBrowser)
+		}(j)
+	}
+	// ------------
+	cntGenerator := o.Readers * 2
+	if mode_debug {
+		cntGenerator = 2
+	}
+	for j := 0; j < cntGenerator; j++ {
+		wg_gen.Add(1)
+		go func(id int) {
+			defer wg_gen.Done()
+			defer g.catch()
+			dataSqlGenerator(ctx_root, sql_generator, id, r, sql_to_write, o.Destination.Driver, cntBrowser)
+		}(j)
+	}
+	// ------------
+	dst_writer_cnt := o.DstWriters
+	for j := 0; j < dst_writer_cnt; j++ {
+		wg_dst_wrt.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_dst_wrt.Done()
+			defer g.catch()
+			tableDstDbWriter(ctx_root, sql_to_write, adbConn, id+len(conSrc), r, stat_monitor, o.NoInsert, o.NoUpdate, o.NoDelete)
+		}(conDst[j], j)
+	}
+	// ------------
+	dst_reader_cnt := o.DstReaders
+	for j := 0; j < dst_reader_cnt; j++ {
+		wg_dst_red.Add(1)
+		go func(adbConn *sql.Conn, id int) {
+			defer wg_dst_red.Done()
+			defer g.catch()
+			tableDstChunkReader(ctx_root, chunks_to_read_on_dst, chunk_comparator, adbConn, r, id+len(conSrc)+dst_reader_cnt, cntBrowser, o.Destination.Driver, stat_monitor)
+		}(conDst[j+dst_writer_cnt], j)
+	}
+	// ------------
+	wg_brw.Wait()
+	log.Print("we are done with browser")
+	// ------------
+	for j := 0; j < cntReader; j++ {
+		select {
+		case <-ctx_root.Done():
+		case pk_chunks_to_read <- synctablechunk{table_id: -1}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d empty chunk to flush readers", cntReader)
+	}
+	wg_red.Wait()
+	log.Print("we are done with reader")
+	// ------------
+	for j := 0; j < dst_reader_cnt; j++ {
+		select {
+		case <-ctx_root.Done():
+		case chunks_to_read_on_dst <- syncdatachunk{}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d empty chunk to flush destination readers", cntReader)
+	}
+	wg_dst_red.Wait()
+	log.Print("we are done with destination reader")
+	// ------------
+	for j := 0; j < cntComparator; j++ {
+		select {
+		case <-ctx_root.Done():
+		case chunk_comparator <- datachunkpair{c_src: nil, c_dst: nil}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d nil pointer to flush comparators", cntComparator)
+	}
+	wg_cmp.Wait()
+	log.Print("we are done with comparators")
+	// ------------
+	for j := 0; j < cntGenerator; j++ {
+		select {
+		case <-ctx_root.Done():
+		case sql_generator <- datarow{table_id: -1}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d nil pointer to flush Generator", cntGenerator)
+	}
+	wg_gen.Wait()
+	log.Print("we are done with sql generators")
+	// ------------
+	for j := 0; j < dst_writer_cnt; j++ {
+		select {
+		case <-ctx_root.Done():
+		case sql_to_write <- syncinsertchunk{table_id: -1}:
+		}
+	}
+	if mode_debug {
+		log.Printf("we added %d nil pointer to flush writers", dst_writer_cnt)
+	}
+	wg_dst_wrt.Wait()
+	log.Print("we are done with writers")
+	// ----------------------------------------------------------------------------------
+	stat_monitor <- action2Stat{TabId: -1}
+	wg_mon.Wait()
+	if ctx_root.Err() != nil {
+		log.Print("sync interrupted , the destination is partially synchronized")
+		return g.Err()
+	}
+	o.Progress.emit(ProgressEvent{Stage: Progress_Done})
+	return nil
+}